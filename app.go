@@ -55,6 +55,9 @@ func New(configFile string) *App {
 		if err != nil {
 			appLogger.Error(errors.Wrapf(err, "Unable to connect to %s connection", k))
 		}
+		// keep checking connection, so application recovers when
+		// database becomes available and readiness can be reported
+		c.StartHealthCheck(c.Details.HealthCheckInterval())
 		connections[k] = c
 	}
 
@@ -140,6 +143,9 @@ func (a *App) Serve() error {
 // Stop the application
 func (a *App) Stop(err error) error {
 	a.Context.Logger.Info("Stopping application...")
+	for _, c := range a.Context.DBConnections {
+		c.StopHealthCheck()
+	}
 	if err != nil && errors.Cause(err) != context.Canceled {
 		return err
 	}
@@ -45,6 +45,33 @@ func (c *Context) Transaction() (*dbe.Connection, error) {
 	return conn.NewTx()
 }
 
+// Ready returns an error when any of application DB connections is not healthy
+func (c *Context) Ready() error {
+	for name, conn := range c.DBConnections {
+		if h := conn.Health(); !h.Healthy {
+			return errors.Errorf("%s connection is not healthy: %s", name, h.Error)
+		}
+	}
+	return nil
+}
+
+// HealthHandler reports health of application DB connections,
+// responds with 503 status when any of connections is not healthy.
+//
+//	r.Get("/health", ctx.HealthHandler)
+func (c *Context) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	health := map[string]dbe.Health{}
+	for name, conn := range c.DBConnections {
+		health[name] = conn.Health()
+	}
+
+	if err := c.Ready(); err != nil {
+		c.JSON(w, http.StatusServiceUnavailable, Response{Data: health, Error: err.Error()})
+		return
+	}
+	c.JSON(w, http.StatusOK, c.ResponseData(health))
+}
+
 // AppSetting gets appSetting string for given key
 func (c *Context) AppSetting(key string) string {
 	if val, ok := c.AppSettings[key]; ok {
@@ -61,6 +88,16 @@ func (c *Context) ResponseData(data interface{}) Response {
 	}
 }
 
+// ResponsePage creates response success object for a page of records.
+// pagination is usually `Query.Paginator` or `Query.CursorPaginator`.
+func (c *Context) ResponsePage(data interface{}, pagination interface{}) Response {
+	return Response{
+		Success:    true,
+		Data:       data,
+		Pagination: pagination,
+	}
+}
+
 // ResponseError creates response error object
 func (c *Context) ResponseError(err error) Response {
 	return Response{
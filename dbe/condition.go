@@ -0,0 +1,169 @@
+package dbe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sedind/flow/dbe/dialect"
+)
+
+// Condition represents composable SQL condition created by
+// condition builders, e.g. `Eq`, `In` or `Or`. It is added to
+// the query as a where Clause with `?` placeholders for its arguments.
+//
+//	q.Filter(
+//		dbe.Eq("status", "active"),
+//		dbe.Or(dbe.Like("name", "%irf%"), dbe.Like("email", "%irf%")),
+//		dbe.Not(dbe.IsNull("confirmed_at")),
+//	)
+type Condition struct {
+	fragment  string
+	arguments []interface{}
+}
+
+// Clause converts condition to Clause with `?` placeholders
+func (c Condition) Clause() Clause {
+	return Clause{c.fragment, c.arguments}
+}
+
+// ToSQL renders condition using placeholders of given dialect
+// and returns it with its arguments in placeholders order
+func (c Condition) ToSQL(d dialect.Dialect) (string, []interface{}) {
+	return d.TranslateSQL(c.fragment), c.arguments
+}
+
+// Expr creates condition from raw SQL fragment and its arguments.
+// Fragment is wrapped in parentheses so it can be safely combined with other conditions.
+func Expr(stmt string, args ...interface{}) Condition {
+	return Condition{fmt.Sprintf("(%s)", stmt), args}
+}
+
+// Eq creates `column = value` condition
+func Eq(column string, value interface{}) Condition {
+	return compare(column, "=", value)
+}
+
+// NotEq creates `column <> value` condition
+func NotEq(column string, value interface{}) Condition {
+	return compare(column, "<>", value)
+}
+
+// Gt creates `column > value` condition
+func Gt(column string, value interface{}) Condition {
+	return compare(column, ">", value)
+}
+
+// Gte creates `column >= value` condition
+func Gte(column string, value interface{}) Condition {
+	return compare(column, ">=", value)
+}
+
+// Lt creates `column < value` condition
+func Lt(column string, value interface{}) Condition {
+	return compare(column, "<", value)
+}
+
+// Lte creates `column <= value` condition
+func Lte(column string, value interface{}) Condition {
+	return compare(column, "<=", value)
+}
+
+// Like creates `column LIKE pattern` condition
+func Like(column string, pattern string) Condition {
+	return compare(column, "LIKE", pattern)
+}
+
+// NotLike creates `column NOT LIKE pattern` condition
+func NotLike(column string, pattern string) Condition {
+	return compare(column, "NOT LIKE", pattern)
+}
+
+// Between creates `column BETWEEN from AND to` condition
+func Between(column string, from interface{}, to interface{}) Condition {
+	return Condition{fmt.Sprintf("%s BETWEEN ? AND ?", column), []interface{}{from, to}}
+}
+
+// IsNull creates `column IS NULL` condition
+func IsNull(column string) Condition {
+	return Condition{fmt.Sprintf("%s IS NULL", column), []interface{}{}}
+}
+
+// IsNotNull creates `column IS NOT NULL` condition
+func IsNotNull(column string) Condition {
+	return Condition{fmt.Sprintf("%s IS NOT NULL", column), []interface{}{}}
+}
+
+// In creates `column IN (values)` condition.
+// Condition is always false when no values are provided.
+func In(column string, values ...interface{}) Condition {
+	if len(values) == 0 {
+		return Condition{"1 = 0", []interface{}{}}
+	}
+	return Condition{fmt.Sprintf("%s IN (%s)", column, placeholders(len(values))), values}
+}
+
+// NotIn creates `column NOT IN (values)` condition.
+// Condition is always true when no values are provided.
+func NotIn(column string, values ...interface{}) Condition {
+	if len(values) == 0 {
+		return Condition{"1 = 1", []interface{}{}}
+	}
+	return Condition{fmt.Sprintf("%s NOT IN (%s)", column, placeholders(len(values))), values}
+}
+
+// And joins conditions with AND operator. Empty conditions are skipped.
+func And(conds ...Condition) Condition {
+	return join(" AND ", conds)
+}
+
+// Or joins conditions with OR operator. Empty conditions are skipped.
+func Or(conds ...Condition) Condition {
+	return join(" OR ", conds)
+}
+
+// Not negates given condition
+func Not(cond Condition) Condition {
+	if cond.fragment == "" {
+		return cond
+	}
+	return Condition{fmt.Sprintf("NOT (%s)", cond.fragment), cond.arguments}
+}
+
+// Filter will append conditions to the where clause of the query like Where
+// does for raw fragments, without expanding `(?)` placeholders. Empty conditions
+// are skipped, so optional filters may be built without string concatenation.
+//
+//	q.Filter(dbe.Eq("status", "active"), dbe.In("role", "admin", "owner"))
+func (q *Query) Filter(conds ...Condition) *Query {
+	for _, cond := range conds {
+		if cond.fragment != "" {
+			q.where(cond.Clause())
+		}
+	}
+	return q
+}
+
+func compare(column string, op string, value interface{}) Condition {
+	return Condition{fmt.Sprintf("%s %s ?", column, op), []interface{}{value}}
+}
+
+func join(sep string, conds []Condition) Condition {
+	cs := Clauses{}
+	for _, cond := range conds {
+		if cond.fragment != "" {
+			cs = append(cs, cond.Clause())
+		}
+	}
+	switch len(cs) {
+	case 0:
+		return Condition{}
+	case 1:
+		return Condition{cs[0].Fragment, cs[0].Arguments}
+	}
+	return Condition{fmt.Sprintf("(%s)", cs.Join(sep)), cs.Args()}
+}
+
+// placeholders creates comma separated list of n `?` placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
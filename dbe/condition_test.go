@@ -0,0 +1,66 @@
+package dbe
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sedind/flow/dbe/dialect"
+)
+
+func TestCondition_Clause(t *testing.T) {
+	tests := []struct {
+		cond     Condition
+		fragment string
+		args     []interface{}
+	}{
+		{Eq("status", "active"), "status = ?", []interface{}{"active"}},
+		{In("role"), "1 = 0", []interface{}{}},
+		{NotIn("role"), "1 = 1", []interface{}{}},
+		{In("role", "admin", "owner"), "role IN (?, ?)", []interface{}{"admin", "owner"}},
+		{Between("age", 18, 65), "age BETWEEN ? AND ?", []interface{}{18, 65}},
+		{And(Eq("a", 1), Condition{}), "a = ?", []interface{}{1}},
+		{Or(), "", nil},
+		{
+			And(Eq("a", 1), Or(Lt("b", 2), Not(In("c", 3, 4))), Gt("d", 5)),
+			"(a = ? AND (b < ? OR NOT (c IN (?, ?))) AND d > ?)",
+			[]interface{}{1, 2, 3, 4, 5},
+		},
+		{Not(Or(IsNull("a"), Expr("b = ? OR c = ?", 1, 2))), "NOT ((a IS NULL OR (b = ? OR c = ?)))", []interface{}{1, 2}},
+	}
+	for _, tt := range tests {
+		c := tt.cond.Clause()
+		if c.Fragment != tt.fragment {
+			t.Errorf("expected %s, got %s", tt.fragment, c.Fragment)
+		}
+		if len(c.Arguments) != len(tt.args) || len(tt.args) > 0 && !reflect.DeepEqual(c.Arguments, tt.args) {
+			t.Errorf("%s: expected arguments %v, got %v", tt.fragment, tt.args, c.Arguments)
+		}
+	}
+}
+
+func TestCondition_ToSQL(t *testing.T) {
+	sql, args := Or(Eq("a", 1), In("b", 2, 3)).ToSQL(dialect.Postgres{})
+	if sql != "(a = $1 OR b IN ($2, $3))" {
+		t.Fatalf("expected postgres placeholders, got %s", sql)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 2, 3}) {
+		t.Fatalf("expected arguments [1 2 3], got %v", args)
+	}
+}
+
+func TestQuery_Filter(t *testing.T) {
+	c := newTestConnection(t, "INSERT INTO users (name, created_at, updated_at) VALUES ('a', 0, 0), ('b', 0, 0), ('c', 0, 0)")
+
+	users := []testUser{}
+	err := c.Query().
+		Where("id > ?", 0).
+		Filter(Or(In("name", "a"), Eq("name", "c")), And(), Not(IsNull("name"))).
+		Order("id").
+		All(&users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Name != "a" || users[1].Name != "c" {
+		t.Fatalf("expected users a and c, got %+v", users)
+	}
+}
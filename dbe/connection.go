@@ -1,6 +1,10 @@
 package dbe
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -8,6 +12,9 @@ import (
 	"github.com/sedind/flow/dbe/dialect"
 )
 
+// savepointID is used to create unique savepoint names
+var savepointID uint64
+
 // Connection represents all of the necessary details for
 // talking with a datastore
 type Connection struct {
@@ -16,6 +23,11 @@ type Connection struct {
 	Dialect dialect.Dialect
 	Store   Store
 	Tx      *Tx
+	ctx     context.Context
+	// replicas used for reads, shared by connection copies
+	replicas *replicaSet
+	health   *healthState
+	hooks    *hookSet
 }
 
 // NewConnection creates a new connection, and sets it's `Dialect`
@@ -33,51 +45,92 @@ func NewConnection(details Details) (*Connection, error) {
 	}
 
 	c := &Connection{
-		ID:      string(time.Now().Unix()),
-		Details: details,
-		Dialect: dialect,
+		ID:       strconv.FormatInt(time.Now().Unix(), 10),
+		Details:  details,
+		Dialect:  dialect,
+		replicas: &replicaSet{},
+		health:   &healthState{},
+		hooks:    &hookSet{},
 	}
 
 	return c, nil
 }
 
-// Open creates new datasource connection
+// Open creates new datasource connection. Failed connection attempts are retried
+// using `RetrySleep`, `RetryMaxSleep`, `RetryLimit` and `RetryTimeout` details.
+// Open can be called again on connection which failed to connect, replicas
+// are opened once the datasource responds.
 func (c *Connection) Open() error {
-	if c.Store != nil {
-		return nil
-	}
+	if c.Store == nil {
+		dbc, err := sqlx.Open(c.Details.Dialect, c.Details.URL)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		dbc.SetMaxOpenConns(c.Details.Pool)
+		dbc.SetMaxIdleConns(c.Details.IdlePool)
 
-	dbc, err := sqlx.Open(c.Details.Dialect, c.Details.URL)
-	if err != nil {
-		return errors.WithStack(err)
+		// store is kept when ping fails, as the pool reconnects on its own
+		// once the database becomes available
+		c.Store = newInstrumentedStore(&db{dbc}, 0, c)
 	}
-	dbc.SetMaxOpenConns(c.Details.Pool)
-	dbc.SetMaxIdleConns(c.Details.IdlePool)
 
-	c.Store = &db{dbc}
-
-	return dbc.Ping()
+	if err := c.pingWithRetry(storePinger(c.Store)); err != nil {
+		return err
+	}
 
+	return c.openReplicas()
 }
 
 // Close destroys an active datasource connection
 func (c *Connection) Close() error {
+	c.StopHealthCheck()
+	if err := c.closeReplicas(); err != nil {
+		return err
+	}
 	return errors.Wrap(c.Store.Close(), "could not close connection")
 }
 
-// NewTx starts a new transaction on the connection
+// Context returns the connection's context. The returned context
+// is always non-nil, it defaults to the background context.
+func (c *Connection) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of connection with its context changed to ctx.
+// Queries, transactions and migrations executed on returned connection
+// are cancelled when ctx is done.
+//
+//	conn.WithContext(r.Context()).Query().All(&users)
+func (c *Connection) WithContext(ctx context.Context) *Connection {
+	if ctx == nil {
+		panic("nil context")
+	}
+	cn := c.copy()
+	cn.ctx = ctx
+	return cn
+}
+
+// NewTx starts a new transaction on the connection.
+// If connection is already in transaction, the same connection is returned,
+// see `Transaction` for nested transactions support.
 func (c *Connection) NewTx() (*Connection, error) {
 	if c.Tx == nil {
-		tx, err := c.Store.Transaction()
+		tx, err := c.Store.TransactionContext(c.Context())
 		if err != nil {
 			return c, errors.Wrap(err, "could not start new transaction")
 		}
 		cn := &Connection{
-			ID:      string(time.Now().Unix()),
+			ID:      strconv.FormatInt(time.Now().Unix(), 10),
 			Details: c.Details,
 			Dialect: c.Dialect,
-			Store:   tx,
+			Store:   newInstrumentedStore(tx, tx.ID, c),
 			Tx:      tx,
+			ctx:     c.ctx,
+			health:  c.health,
+			hooks:   c.hooks,
 		}
 		return cn, nil
 	}
@@ -100,12 +153,88 @@ func (c *Connection) Commit() error {
 	return errors.New("Current connection does not have transaction")
 }
 
+// Transaction runs fn within a transaction. Transaction is committed when
+// fn returns nil and rolled back when fn returns an error or panics.
+// Nested calls on a transaction connection use savepoints, so only
+// changes made by the failed nested fn are rolled back.
+//
+//	err := conn.Transaction(func(tx *dbe.Connection) error {
+//		return tx.Create(&user)
+//	})
+func (c *Connection) Transaction(fn func(tx *Connection) error) (err error) {
+	if c.Tx != nil {
+		return c.savepoint(fn)
+	}
+
+	tx, err := c.NewTx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return errors.Wrapf(err, "could not rollback transaction: %s", rerr)
+		}
+		return err
+	}
+
+	return errors.Wrap(tx.Commit(), "could not commit transaction")
+}
+
+// savepoint runs fn within a savepoint of current transaction
+func (c *Connection) savepoint(fn func(tx *Connection) error) (err error) {
+	name := fmt.Sprintf("flow_sp_%d", atomic.AddUint64(&savepointID, 1))
+
+	if err = c.execSavepointStmt(c.Dialect.SavepointStmt, name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.execSavepointStmt(c.Dialect.RollbackToSavepointStmt, name)
+			panic(r)
+		}
+	}()
+
+	if err = fn(c); err != nil {
+		if rerr := c.execSavepointStmt(c.Dialect.RollbackToSavepointStmt, name); rerr != nil {
+			return errors.Wrapf(err, "could not rollback to savepoint: %s", rerr)
+		}
+		return err
+	}
+
+	return c.execSavepointStmt(c.Dialect.ReleaseSavepointStmt, name)
+}
+
+// execSavepointStmt executes savepoint statement created by given dialect function
+func (c *Connection) execSavepointStmt(stmtFn func(string) (string, error), name string) error {
+	stmt, err := stmtFn(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = c.Store.ExecContext(c.Context(), stmt)
+	return errors.Wrapf(err, "could not execute %s", stmt)
+}
+
 func (c *Connection) copy() *Connection {
 	return &Connection{
-		ID:      string(time.Now().Unix()),
+		ID:      strconv.FormatInt(time.Now().Unix(), 10),
 		Details: c.Details,
 		Dialect: c.Dialect,
 		Store:   c.Store,
 		Tx:      c.Tx,
+		ctx:     c.ctx,
+
+		replicas: c.replicas,
+		health:   c.health,
+		hooks:    c.hooks,
 	}
 }
@@ -0,0 +1,202 @@
+package dbe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Health represents the state of connection reported by the last health check
+type Health struct {
+	Healthy         bool      `json:"healthy"`
+	Error           string    `json:"error,omitempty"`
+	CheckedAt       time.Time `json:"checked_at"`
+	Replicas        int       `json:"replicas,omitempty"`
+	HealthyReplicas int       `json:"healthy_replicas,omitempty"`
+}
+
+// healthState holds health of a connection, shared by its copies
+type healthState struct {
+	mu     sync.RWMutex
+	health Health
+	stop   chan struct{}
+}
+
+// pinger is implemented by stores which can verify the datasource connection
+type pinger interface {
+	PingContext(context.Context) error
+}
+
+// execPinger verifies the datasource connection of stores which can not ping
+type execPinger struct {
+	Store
+}
+
+// PingContext executes trivial statement
+func (p execPinger) PingContext(ctx context.Context) error {
+	_, err := p.ExecContext(ctx, "SELECT 1")
+	return err
+}
+
+// storePinger returns pinger of store, unwrapping instrumented store
+func storePinger(s Store) pinger {
+	if is, ok := s.(*instrumentedStore); ok {
+		s = is.Store
+	}
+	if p, ok := s.(pinger); ok {
+		return p
+	}
+	return execPinger{s}
+}
+
+// Ping verifies connections to the datasource and its replicas are alive
+// and records the result, which is returned by `Health`. Unavailable
+// replicas are excluded from reads until they respond again.
+func (c *Connection) Ping() error {
+	if c.Store == nil {
+		return c.setHealth(errors.New("connection is not open"))
+	}
+
+	err := storePinger(c.Store).PingContext(c.Context())
+	if err == nil {
+		// replicas were not opened when datasource was unavailable on Open
+		if rerr := c.openReplicas(); rerr != nil {
+			Logger.Warn(rerr)
+		}
+	}
+
+	for _, r := range c.replicas.list() {
+		if rerr := storePinger(r.store).PingContext(c.Context()); rerr != nil {
+			r.markDown(c.Details.ReplicaRetry())
+		} else {
+			r.markUp()
+		}
+	}
+
+	return c.setHealth(errors.Wrap(err, "could not ping database"))
+}
+
+// Health returns the state of connection reported by the last `Ping`,
+// either called directly or by the health check started with `StartHealthCheck`.
+func (c *Connection) Health() Health {
+	if c.health == nil {
+		return Health{}
+	}
+	c.health.mu.RLock()
+	defer c.health.mu.RUnlock()
+	return c.health.health
+}
+
+// StartHealthCheck pings the datasource every interval in background
+// and logs the connection state changes. Call `StopHealthCheck` or `Close`
+// to stop checking.
+//
+//	c.StartHealthCheck(c.Details.HealthCheckInterval())
+func (c *Connection) StartHealthCheck(interval time.Duration) {
+	if c.health == nil || interval <= 0 {
+		return
+	}
+
+	c.health.mu.Lock()
+	defer c.health.mu.Unlock()
+	if c.health.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	c.health.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				c.Ping()
+			}
+		}
+	}()
+}
+
+// StopHealthCheck stops the background health check
+func (c *Connection) StopHealthCheck() {
+	if c.health == nil {
+		return
+	}
+
+	c.health.mu.Lock()
+	defer c.health.mu.Unlock()
+	if c.health.stop != nil {
+		close(c.health.stop)
+		c.health.stop = nil
+	}
+}
+
+// setHealth records the result of health check and logs state changes.
+// Given error is returned unchanged.
+func (c *Connection) setHealth(err error) error {
+	if c.health == nil {
+		return err
+	}
+
+	h := Health{
+		Healthy:   err == nil,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		h.Error = err.Error()
+	}
+	replicas := c.replicas.list()
+	h.Replicas = len(replicas)
+	for _, r := range replicas {
+		if r.healthy() {
+			h.HealthyReplicas++
+		}
+	}
+
+	c.health.mu.Lock()
+	prev := c.health.health
+	c.health.health = h
+	c.health.mu.Unlock()
+
+	switch {
+	case !h.Healthy && (prev.Healthy || prev.CheckedAt.IsZero()):
+		Logger.Warnf("%s database is not available: %s", c.Details.Database, h.Error)
+	case h.Healthy && !prev.Healthy && !prev.CheckedAt.IsZero():
+		Logger.Infof("%s database is available again", c.Details.Database)
+	}
+	return err
+}
+
+// pingWithRetry pings the datasource until it responds, retrying failed
+// connection attempts with exponential backoff starting at `RetrySleep`
+// at most `RetryLimit` times and no longer than `RetryTimeout`.
+func (c *Connection) pingWithRetry(p pinger) error {
+	sleep := c.Details.RetrySleep()
+	maxSleep := c.Details.RetryMaxSleep()
+	limit := c.Details.RetryLimit()
+
+	ctx, cancel := context.WithTimeout(c.Context(), c.Details.RetryTimeout())
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		err := p.PingContext(ctx)
+		if err == nil || attempt >= limit || !isConnectionError(err) {
+			return c.setHealth(err)
+		}
+
+		Logger.Debugf("could not connect to %s database, retrying in %s: %s", c.Details.Database, sleep, err)
+		select {
+		case <-ctx.Done():
+			return c.setHealth(errors.Wrapf(err, "could not connect within %s", c.Details.RetryTimeout()))
+		case <-time.After(sleep):
+		}
+
+		if sleep *= 2; sleep > maxSleep {
+			sleep = maxSleep
+		}
+	}
+}
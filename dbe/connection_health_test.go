@@ -0,0 +1,103 @@
+package dbe
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingPinger fails given number of pings with err
+type failingPinger struct {
+	failures int
+	err      error
+	pings    int
+}
+
+func (p *failingPinger) PingContext(ctx context.Context) error {
+	p.pings++
+	if p.pings <= p.failures {
+		return p.err
+	}
+	return nil
+}
+
+func TestConnection_PingWithRetry(t *testing.T) {
+	c := newTestConnection(t)
+	c.Details.Options = map[string]string{"retry_sleep": "1ms", "retry_max_sleep": "2ms", "retry_limit": "3"}
+
+	p := &failingPinger{failures: 3, err: driver.ErrBadConn}
+	if err := c.pingWithRetry(p); err != nil {
+		t.Fatal(err)
+	}
+	if p.pings != 4 {
+		t.Fatalf("expected 4 pings, got %d", p.pings)
+	}
+	if !c.Health().Healthy {
+		t.Fatal("expected healthy connection")
+	}
+
+	p = &failingPinger{failures: 5, err: driver.ErrBadConn}
+	if err := c.pingWithRetry(p); err == nil {
+		t.Fatal("expected error when retry limit is exceeded")
+	}
+	if p.pings != 4 {
+		t.Fatalf("expected 4 pings, got %d", p.pings)
+	}
+	if h := c.Health(); h.Healthy || h.Error == "" {
+		t.Fatalf("expected unhealthy connection, got %+v", h)
+	}
+}
+
+func TestConnection_PingWithRetryTimeout(t *testing.T) {
+	c := newTestConnection(t)
+	c.Details.Options = map[string]string{"retry_sleep": "50ms", "retry_timeout": "10ms"}
+
+	start := time.Now()
+	err := c.pingWithRetry(&failingPinger{failures: 100, err: driver.ErrBadConn})
+	if err == nil || !strings.Contains(err.Error(), "could not connect within 10ms") {
+		t.Fatalf("expected retry timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected retrying to stop after timeout, took %s", elapsed)
+	}
+}
+
+func TestConnection_PingWithRetryNonConnectionError(t *testing.T) {
+	c := newTestConnection(t)
+
+	p := &failingPinger{failures: 100, err: errTest}
+	if err := c.pingWithRetry(p); err != errTest {
+		t.Fatalf("expected test error, got %v", err)
+	}
+	if p.pings != 1 {
+		t.Fatalf("expected single ping, got %d", p.pings)
+	}
+}
+
+func TestConnection_Ping(t *testing.T) {
+	c := newTestConnection(t)
+
+	if err := c.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if h := c.Health(); !h.Healthy || h.CheckedAt.IsZero() {
+		t.Fatalf("expected healthy connection, got %+v", h)
+	}
+
+	c.StartHealthCheck(time.Millisecond)
+	c.StartHealthCheck(time.Millisecond)
+	c.StopHealthCheck()
+	c.StopHealthCheck()
+
+	if err := c.Store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Ping(); err == nil {
+		t.Fatal("expected error of closed connection")
+	}
+	if c.Health().Healthy {
+		t.Fatal("expected unhealthy connection")
+	}
+}
@@ -0,0 +1,166 @@
+package dbe
+
+import (
+	"database/sql/driver"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// replica is a read-only datasource connection
+type replica struct {
+	url   string
+	store Store
+	// downUntil is the unix time in nanoseconds until replica is considered unhealthy
+	downUntil int64
+}
+
+// healthy reports whether replica can be used for reads
+func (r *replica) healthy() bool {
+	return atomic.LoadInt64(&r.downUntil) < time.Now().UnixNano()
+}
+
+// markDown excludes replica from reads for given duration
+func (r *replica) markDown(d time.Duration) {
+	atomic.StoreInt64(&r.downUntil, time.Now().Add(d).UnixNano())
+}
+
+// markUp includes replica in reads
+func (r *replica) markUp() {
+	atomic.StoreInt64(&r.downUntil, 0)
+}
+
+// replicaSet holds replicas of a connection, shared by its copies
+type replicaSet struct {
+	mu       sync.RWMutex
+	replicas []*replica
+	opened   bool
+	next     uint64
+}
+
+// list returns replicas of the set
+func (rs *replicaSet) list() []*replica {
+	if rs == nil {
+		return nil
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.replicas
+}
+
+// pick returns next healthy replica in round-robin order,
+// nil is returned when there are no healthy replicas
+func (rs *replicaSet) pick() *replica {
+	replicas := rs.list()
+	n := len(replicas)
+	for i := 0; i < n; i++ {
+		r := replicas[int(atomic.AddUint64(&rs.next, 1)%uint64(n))]
+		if r.healthy() {
+			return r
+		}
+	}
+	return nil
+}
+
+// openReplicas opens connections to all replicas listed in connection details,
+// replicas which are already opened are kept. Replicas which can not be reached
+// are marked as unhealthy.
+func (c *Connection) openReplicas() error {
+	rs := c.replicas
+	if rs == nil || len(c.Details.Replicas) == 0 {
+		return nil
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.opened {
+		return nil
+	}
+
+	replicas := []*replica{}
+	for _, url := range c.Details.Replicas {
+		dbc, err := sqlx.Open(c.Details.Dialect, url)
+		if err != nil {
+			return errors.Wrap(err, "could not open replica connection")
+		}
+		dbc.SetMaxOpenConns(c.Details.Pool)
+		dbc.SetMaxIdleConns(c.Details.IdlePool)
+
+		r := &replica{url: url, store: newInstrumentedStore(&db{dbc}, 0, c)}
+		if err := dbc.PingContext(c.Context()); err != nil {
+			Logger.Warnf("replica is not available: %s", err)
+			r.markDown(c.Details.ReplicaRetry())
+		}
+		replicas = append(replicas, r)
+	}
+	rs.replicas = replicas
+	rs.opened = true
+	return nil
+}
+
+// closeReplicas closes connections to all replicas
+func (c *Connection) closeReplicas() error {
+	for _, r := range c.replicas.list() {
+		if err := r.store.Close(); err != nil {
+			return errors.Wrap(err, "could not close replica connection")
+		}
+	}
+	return nil
+}
+
+// replica returns replica which should be used for reads,
+// nil is returned when reads should go to the primary
+func (c *Connection) replica() *replica {
+	if c.replicas == nil || c.Tx != nil {
+		return nil
+	}
+	return c.replicas.pick()
+}
+
+// UsePrimary forces the query to read from the primary datasource even
+// when connection has replicas, so recently written records are visible.
+//
+//	c.Create(&user)
+//	c.Query().UsePrimary().Find(&user, user.ID)
+func (q *Query) UsePrimary() *Query {
+	q.usePrimary = true
+	return q
+}
+
+// read executes fn against a healthy replica or the primary datasource.
+// Replica is marked as unhealthy and fn is executed against the primary
+// when replica connection fails.
+func (q *Query) read(fn func(s Store) error) error {
+	var r *replica
+	if !q.usePrimary {
+		r = q.Connection.replica()
+	}
+	if r == nil {
+		return fn(q.Connection.Store)
+	}
+
+	err := fn(r.store)
+	if isConnectionError(err) {
+		Logger.Warnf("replica is not available: %s", err)
+		r.markDown(q.Connection.Details.ReplicaRetry())
+		return fn(q.Connection.Store)
+	}
+	return err
+}
+
+// isConnectionError reports whether err is caused by broken datasource connection
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	err = errors.Cause(err)
+	if err == driver.ErrBadConn {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
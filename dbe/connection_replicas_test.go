@@ -0,0 +1,79 @@
+package dbe
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConnection_Replicas(t *testing.T) {
+	replica := newTestConnection(t, "INSERT INTO users (name, created_at, updated_at) VALUES ('replica', 0, 0)")
+	primary := newTestConnection(t, "INSERT INTO users (name, created_at, updated_at) VALUES ('primary', 0, 0)")
+
+	c, err := NewConnection(Details{
+		Dialect:  "sqlite",
+		Database: primary.Details.Database,
+		Replicas: []string{replica.Details.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	name := func(q *Query) string {
+		t.Helper()
+		u := testUser{}
+		if err := q.First(&u); err != nil {
+			t.Fatal(err)
+		}
+		return u.Name
+	}
+
+	if n := name(c.Query()); n != "replica" {
+		t.Fatalf("expected read from replica, got %s", n)
+	}
+	if n := name(c.Query().UsePrimary()); n != "primary" {
+		t.Fatalf("expected read from primary, got %s", n)
+	}
+	err = c.Transaction(func(tx *Connection) error {
+		if n := name(tx.Query()); n != "primary" {
+			t.Fatalf("expected read from primary within transaction, got %s", n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.replicas.list()[0].markDown(time.Minute)
+	if n := name(c.Query()); n != "primary" {
+		t.Fatalf("expected read from primary when replica is down, got %s", n)
+	}
+}
+
+func TestConnection_ReplicaUnavailable(t *testing.T) {
+	primary := newTestConnection(t)
+
+	c, err := NewConnection(Details{
+		Dialect:  "sqlite",
+		Database: primary.Details.Database,
+		Replicas: []string{"file:" + filepath.Join(t.TempDir(), "missing", "replica.db")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	if r := c.replica(); r != nil {
+		t.Fatalf("expected unavailable replica not to be used, got %s", r.url)
+	}
+	if err := c.Query().All(&[]testUser{}); err != nil {
+		t.Fatal(err)
+	}
+}
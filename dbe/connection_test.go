@@ -0,0 +1,109 @@
+package dbe
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var errTest = errors.New("test error")
+
+// testUser is model of users table created by newTestConnection
+type testUser struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (testUser) TableName() string { return "users" }
+
+// newTestConnection opens connection to new SQLite database with users
+// table, given statements are executed to prepare additional tables
+func newTestConnection(t *testing.T, stmts ...string) *Connection {
+	t.Helper()
+
+	c, err := NewConnection(Details{Dialect: "sqlite", Database: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	stmts = append([]string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, created_at DATETIME, updated_at DATETIME)",
+	}, stmts...)
+	for _, stmt := range stmts {
+		if _, err := c.Store.Exec(stmt); err != nil {
+			t.Fatalf("%s: %s", stmt, err)
+		}
+	}
+	return c
+}
+
+func TestConnection_Transaction(t *testing.T) {
+	c := newTestConnection(t)
+
+	err := c.Transaction(func(tx *Connection) error {
+		if err := tx.Create(&testUser{Name: "kept"}); err != nil {
+			return err
+		}
+		// failed nested transaction is rolled back to its savepoint
+		tx.Transaction(func(tx *Connection) error {
+			if err := tx.Create(&testUser{Name: "rolled back"}); err != nil {
+				return err
+			}
+			return errTest
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := []testUser{}
+	if err := c.Query().All(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "kept" {
+		t.Fatalf("expected only kept user, got %+v", users)
+	}
+}
+
+func TestConnection_TransactionRollback(t *testing.T) {
+	c := newTestConnection(t)
+
+	err := c.Transaction(func(tx *Connection) error {
+		if err := tx.Create(&testUser{Name: "committed"}); err != nil {
+			return err
+		}
+		return tx.Transaction(func(tx *Connection) error {
+			return tx.Create(&testUser{Name: "nested"})
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Transaction(func(tx *Connection) error {
+		if err := tx.Create(&testUser{Name: "rolled back"}); err != nil {
+			return err
+		}
+		return errTest
+	})
+	if errors.Cause(err) != errTest {
+		t.Fatalf("expected test error, got %v", err)
+	}
+
+	n, err := c.Query().Count(&testUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 users of committed transaction, got %d", n)
+	}
+}
@@ -1,6 +1,10 @@
 package dbe
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
 
 // db struct is sqlx.DB wrapper usedd to implement Store interface
 type db struct {
@@ -8,7 +12,11 @@ type db struct {
 }
 
 func (db *db) Transaction() (*Tx, error) {
-	return newTx(db)
+	return newTx(context.Background(), db)
+}
+
+func (db *db) TransactionContext(ctx context.Context) (*Tx, error) {
+	return newTx(ctx, db)
 }
 
 func (db *db) Rollback() error {
@@ -1,12 +1,16 @@
 package dbe
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	_mysql "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"           // PostgreSQL driver
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
 	"github.com/sedind/flow/defaults"
 
 	"github.com/pkg/errors"
@@ -24,10 +28,36 @@ type Details struct {
 	Pool     int               `yaml:"pool"`
 	IdlePool int               `yaml:"idle_pool"`
 	Options  map[string]string `yaml:"options"`
+	// Replicas lists URLs of read-only replicas using the same dialect
+	Replicas []string `yaml:"replicas"`
 }
 
 var dialectRegex = regexp.MustCompile(`\s+:\/\/`)
 
+// postgresOptions lists Options keys which are passed to PostgreSQL driver
+var postgresOptions = []string{
+	"application_name",
+	"connect_timeout",
+	"search_path",
+	"sslcert",
+	"sslkey",
+	"sslmode",
+	"sslrootcert",
+}
+
+// sqliteOptions lists Options keys which are passed to SQLite driver
+var sqliteOptions = []string{
+	"_busy_timeout",
+	"_foreign_keys",
+	"_journal_mode",
+	"_synchronous",
+	"cache",
+	"mode",
+}
+
+// sqliteMemory is the SQLite database name for in-memory database
+const sqliteMemory = ":memory:"
+
 // Finalize cleans up the connection details by normalizing names
 func (d *Details) Finalize() error {
 
@@ -60,12 +90,91 @@ func (d *Details) Finalize() error {
 			d.Port = defaults.String(d.Port, "3306")
 			d.Database = strings.TrimPrefix(d.Database, "/")
 		}
+	case "postgres", "postgresql", "pg":
+		d.Dialect = "postgres"
+		if d.URL != "" {
+			return d.parsePostgresURL()
+		}
+		d.Port = defaults.String(d.Port, "5432")
+		d.Database = strings.TrimPrefix(d.Database, "/")
+		d.URL = d.buildPostgresURL()
+	case "sqlite3", "sqlite":
+		d.Dialect = "sqlite3"
+		if d.URL != "" {
+			d.Database = strings.TrimPrefix(strings.SplitN(d.URL, "?", 2)[0], "file:")
+		} else {
+			d.URL = d.buildSQLiteURL()
+		}
+		if d.Database == "" {
+			return errors.New("SQLite database path or `:memory:` must be provided")
+		}
+		if d.Database == sqliteMemory {
+			// every new connection to in-memory database creates
+			// an empty database, so pool must hold a single connection
+			d.Pool = 1
+			d.IdlePool = 1
+		}
 	default:
 		return errors.Errorf("Unsupported dialect `%s`!", d.Dialect)
 	}
 	return nil
 }
 
+// parsePostgresURL fills connection details from PostgreSQL URL
+func (d *Details) parsePostgresURL() error {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return errors.Wrap(err, "The URL is not supported by PostgreSQL driver")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return errors.Errorf("The URL scheme `%s` is not supported by PostgreSQL driver", u.Scheme)
+	}
+
+	if u.User != nil {
+		d.User = u.User.Username()
+		d.Password, _ = u.User.Password()
+	}
+	d.Host = u.Hostname()
+	d.Port = defaults.String(u.Port(), "5432")
+	d.Database = strings.TrimPrefix(u.Path, "/")
+
+	if d.Options == nil {
+		d.Options = map[string]string{}
+	}
+	for k, v := range u.Query() {
+		if _, ok := d.Options[k]; !ok && len(v) > 0 {
+			d.Options[k] = v[0]
+		}
+	}
+	return nil
+}
+
+// buildPostgresURL creates PostgreSQL URL from connection details
+func (d *Details) buildPostgresURL() string {
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   d.Host + ":" + d.Port,
+		Path:   "/" + d.Database,
+	}
+	if d.User != "" {
+		if d.Password != "" {
+			u.User = url.UserPassword(d.User, d.Password)
+		} else {
+			u.User = url.User(d.User)
+		}
+	}
+
+	q := url.Values{}
+	for _, k := range postgresOptions {
+		if v, ok := d.Options[k]; ok {
+			q.Set(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
 // RetrySleep returns the amount of time to wait between two connection retries
 func (d *Details) RetrySleep() time.Duration {
 	dur, err := time.ParseDuration(defaults.String(d.Options["retry_sleep"], "1ms"))
@@ -84,7 +193,79 @@ func (d *Details) RetryLimit() int {
 	return i
 }
 
+// RetryTimeout returns the maximum amount of time spent retrying to connect
+// when connection is opened, later recovery is left to the health check
+func (d *Details) RetryTimeout() time.Duration {
+	dur, err := time.ParseDuration(defaults.String(d.Options["retry_timeout"], "10s"))
+	if err != nil {
+		return 10 * time.Second
+	}
+	return dur
+}
+
+// RetryMaxSleep returns the maximum amount of time to wait between two connection retries
+func (d *Details) RetryMaxSleep() time.Duration {
+	dur, err := time.ParseDuration(defaults.String(d.Options["retry_max_sleep"], "1s"))
+	if err != nil {
+		return 1 * time.Second
+	}
+	return dur
+}
+
+// HealthCheckInterval returns the amount of time between two background health checks,
+// health checking is disabled when interval is zero
+func (d *Details) HealthCheckInterval() time.Duration {
+	dur, err := time.ParseDuration(defaults.String(d.Options["health_check_interval"], "10s"))
+	if err != nil {
+		return 10 * time.Second
+	}
+	return dur
+}
+
+// SlowQueryThreshold returns the duration after which executed statement
+// is logged as slow query, slow queries are not reported when threshold is zero
+func (d *Details) SlowQueryThreshold() time.Duration {
+	dur, err := time.ParseDuration(defaults.String(d.Options["slow_query_threshold"], "1s"))
+	if err != nil {
+		return 1 * time.Second
+	}
+	return dur
+}
+
+// ReplicaRetry returns the amount of time unavailable replica is excluded from reads
+func (d *Details) ReplicaRetry() time.Duration {
+	dur, err := time.ParseDuration(defaults.String(d.Options["replica_retry"], "30s"))
+	if err != nil {
+		return 30 * time.Second
+	}
+	return dur
+}
+
+// MigrationLockTimeout returns the maximum amount of time migrator waits
+// for migration lock held by another migrator
+func (d *Details) MigrationLockTimeout() time.Duration {
+	dur, err := time.ParseDuration(defaults.String(d.Options["migration_lock_timeout"], "1m"))
+	if err != nil {
+		return 1 * time.Minute
+	}
+	return dur
+}
+
 // MigrationTableName returns the name of the table to track migrations
 func (d *Details) MigrationTableName() string {
 	return defaults.String(d.Options["migration_table_name"], "schema_migration")
 }
+
+// buildSQLiteURL creates SQLite data source name from connection details
+func (d *Details) buildSQLiteURL() string {
+	q := url.Values{}
+	for _, k := range sqliteOptions {
+		if v, ok := d.Options[k]; ok {
+			q.Set(k, v)
+		}
+	}
+	if len(q) == 0 {
+		return d.Database
+	}
+	return fmt.Sprintf("file:%s?%s", d.Database, q.Encode())
+}
@@ -2,6 +2,8 @@ package dialect
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 var _ Dialect = Common{}
@@ -22,6 +24,19 @@ func (c Common) CreateStmt(tableName string, columns string, columnNames string)
 	return query, nil
 }
 
+// CreateManyStmt creates SQL INSERT statement for multiple rows
+// where each of values holds a single row values list
+func (c Common) CreateManyStmt(tableName string, columns string, values []string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", tableName, columns, strings.Join(values, ", "))
+	return query, nil
+}
+
+// UpsertStmt creates SQL statement which inserts a row or updates
+// updateColumns of existing row conflicting on conflictColumns
+func (c Common) UpsertStmt(tableName string, columns string, columnNames string, conflictColumns []string, updateColumns []string) (string, error) {
+	return "", fmt.Errorf("upsert is not supported by '%s' dialect", c.Name())
+}
+
 // UpdateStmt createse SQL INSER statement
 func (c Common) UpdateStmt(tableName string, columns string, where string) (string, error) {
 
@@ -41,6 +56,61 @@ func (c Common) CountStmt(field string, query string) (string, error) {
 	return stmt, nil
 }
 
+// SavepointStmt creates SQL statement which sets named transaction savepoint
+func (c Common) SavepointStmt(name string) (string, error) {
+	return fmt.Sprintf("SAVEPOINT %s", name), nil
+}
+
+// RollbackToSavepointStmt creates SQL statement which rolls back transaction to named savepoint
+func (c Common) RollbackToSavepointStmt(name string) (string, error) {
+	return fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", name), nil
+}
+
+// ReleaseSavepointStmt creates SQL statement which releases named savepoint
+func (c Common) ReleaseSavepointStmt(name string) (string, error) {
+	return fmt.Sprintf("RELEASE SAVEPOINT %s", name), nil
+}
+
+// LockStmt creates SQL statement which acquires named database-level lock
+// waiting at most timeout. Statement returns 1 when lock is acquired.
+// Dialects without database-level locks always acquire it, so no lock is held.
+func (c Common) LockStmt(name string, timeout time.Duration) (string, error) {
+	return "SELECT 1", nil
+}
+
+// UnlockStmt creates SQL statement which releases named database-level lock
+func (c Common) UnlockStmt(name string) (string, error) {
+	return "SELECT 1", nil
+}
+
+// InsertReturnsID reports if CreateStmt returns id of inserted row
+// instead of relying on driver LastInsertId
+func (c Common) InsertReturnsID() bool {
+	return false
+}
+
+// InsertManyReturnsIDs reports if CreateManyStmt returns ids of inserted rows.
+// Otherwise driver LastInsertId must report id of the first inserted row.
+func (c Common) InsertManyReturnsIDs() bool {
+	return false
+}
+
+// UpsertReturnsID reports if UpsertStmt returns id of inserted or updated row
+// instead of relying on driver LastInsertId
+func (c Common) UpsertReturnsID() bool {
+	return false
+}
+
+// QuoteIdent quotes identifier using double quotes
+func (c Common) QuoteIdent(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// quoteString quotes string literal using single quotes
+func quoteString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
 // TranslateSQL to supported dialect
 func (c Common) TranslateSQL(sql string) string {
 	return sql
@@ -0,0 +1,165 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column describes table column in dialect-neutral way
+type Column struct {
+	Name string
+	// Type is one of string, text, int, bigint, bool, float, decimal,
+	// timestamp, date, uuid, blob or json. Other types are used as they are.
+	Type string
+	// Size of string column, 255 when not set
+	Size    int
+	Null    bool
+	Primary bool
+	// Default is SQL expression of column default value
+	Default string
+}
+
+// Index describes table index
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// columnTypes maps dialect-neutral column types to dialect column types
+type columnTypes struct {
+	types map[string]string
+	// serial holds definitions of auto incremented primary key by type
+	serial map[string]string
+}
+
+var commonTypes = columnTypes{
+	types: map[string]string{
+		"string":    "VARCHAR(%d)",
+		"text":      "TEXT",
+		"int":       "INTEGER",
+		"bigint":    "BIGINT",
+		"bool":      "BOOLEAN",
+		"float":     "FLOAT",
+		"decimal":   "DECIMAL",
+		"timestamp": "TIMESTAMP",
+		"date":      "DATE",
+		"uuid":      "CHAR(36)",
+		"blob":      "BLOB",
+		"json":      "TEXT",
+	},
+	serial: map[string]string{},
+}
+
+// columnType returns dialect column type of column
+func (ct columnTypes) columnType(c Column) string {
+	t, ok := ct.types[c.Type]
+	if !ok {
+		return c.Type
+	}
+	if strings.Contains(t, "%d") {
+		size := c.Size
+		if size == 0 {
+			size = 255
+		}
+		t = fmt.Sprintf(t, size)
+	}
+	return t
+}
+
+// columnDef creates column definition, primary key constraint
+// is added only when inline is true
+func (ct columnTypes) columnDef(c Column, inline bool) string {
+	if c.Primary && inline {
+		if serial, ok := ct.serial[c.Type]; ok {
+			return fmt.Sprintf("%s %s", c.Name, serial)
+		}
+		return fmt.Sprintf("%s %s PRIMARY KEY", c.Name, ct.columnType(c))
+	}
+
+	def := fmt.Sprintf("%s %s", c.Name, ct.columnType(c))
+	if c.Null && !c.Primary {
+		def += " NULL"
+	} else {
+		def += " NOT NULL"
+	}
+	if c.Default != "" {
+		def += " DEFAULT " + c.Default
+	}
+	return def
+}
+
+// createTableStmt creates SQL CREATE TABLE statement
+func (ct columnTypes) createTableStmt(tableName string, columns []Column) (string, error) {
+	if len(columns) == 0 {
+		return "", fmt.Errorf("table %s must have at least one column", tableName)
+	}
+
+	primary := []string{}
+	for _, c := range columns {
+		if c.Primary {
+			primary = append(primary, c.Name)
+		}
+	}
+
+	defs := []string{}
+	for _, c := range columns {
+		defs = append(defs, "\t"+ct.columnDef(c, len(primary) == 1))
+	}
+	if len(primary) > 1 {
+		defs = append(defs, fmt.Sprintf("\tPRIMARY KEY (%s)", strings.Join(primary, ", ")))
+	}
+
+	query := fmt.Sprintf("CREATE TABLE %s (\n%s\n)", tableName, strings.Join(defs, ",\n"))
+	return query, nil
+}
+
+// addColumnStmt creates SQL ALTER TABLE ADD COLUMN statement
+func (ct columnTypes) addColumnStmt(tableName string, column Column) (string, error) {
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableName, ct.columnDef(column, true))
+	return query, nil
+}
+
+// CreateTableStmt creates SQL CREATE TABLE statement
+func (c Common) CreateTableStmt(tableName string, columns []Column) (string, error) {
+	return commonTypes.createTableStmt(tableName, columns)
+}
+
+// DropTableStmt creates SQL DROP TABLE statement
+func (c Common) DropTableStmt(tableName string) (string, error) {
+	return fmt.Sprintf("DROP TABLE %s", tableName), nil
+}
+
+// AddColumnStmt creates SQL statement which adds column to table
+func (c Common) AddColumnStmt(tableName string, column Column) (string, error) {
+	return commonTypes.addColumnStmt(tableName, column)
+}
+
+// DropColumnStmt creates SQL statement which drops column of table
+func (c Common) DropColumnStmt(tableName string, column string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, column), nil
+}
+
+// RenameColumnStmt creates SQL statement which renames column of table
+func (c Common) RenameColumnStmt(tableName string, oldName string, newName string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", tableName, oldName, newName), nil
+}
+
+// AddIndexStmt creates SQL CREATE INDEX statement
+func (c Common) AddIndexStmt(tableName string, index Index) (string, error) {
+	if len(index.Columns) == 0 {
+		return "", fmt.Errorf("index %s must have at least one column", index.Name)
+	}
+
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	query := fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, index.Name, tableName, strings.Join(index.Columns, ", "))
+	return query, nil
+}
+
+// DropIndexStmt creates SQL DROP INDEX statement
+func (c Common) DropIndexStmt(tableName string, name string) (string, error) {
+	return fmt.Sprintf("DROP INDEX %s", name), nil
+}
@@ -0,0 +1,99 @@
+package dialect
+
+import "testing"
+
+func TestCreateTableStmt(t *testing.T) {
+	columns := []Column{
+		{Name: "id", Type: "int", Primary: true},
+		{Name: "email", Type: "string", Size: 100},
+		{Name: "age", Type: "int", Null: true, Default: "0"},
+		{Name: "created_at", Type: "timestamp"},
+	}
+
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"postgres", `CREATE TABLE users (
+	id SERIAL PRIMARY KEY,
+	email VARCHAR(100) NOT NULL,
+	age INTEGER NULL DEFAULT 0,
+	created_at TIMESTAMP NOT NULL
+)`},
+		{"mysql", `CREATE TABLE users (
+	id INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	email VARCHAR(100) NOT NULL,
+	age INT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL
+)`},
+		{"sqlite3", `CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email VARCHAR(100) NOT NULL,
+	age INTEGER NULL DEFAULT 0,
+	created_at DATETIME NOT NULL
+)`},
+	}
+	for _, tt := range tests {
+		d, err := New(tt.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt, err := d.CreateTableStmt("users", columns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stmt != tt.expected {
+			t.Errorf("%s: unexpected statement:\n%s", tt.dialect, stmt)
+		}
+	}
+}
+
+func TestCreateTableStmt_CompositePrimaryKey(t *testing.T) {
+	d, err := New("postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := d.CreateTableStmt("user_roles", []Column{
+		{Name: "user_id", Type: "int", Primary: true},
+		{Name: "role_id", Type: "int", Primary: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `CREATE TABLE user_roles (
+	user_id INTEGER NOT NULL,
+	role_id INTEGER NOT NULL,
+	PRIMARY KEY (user_id, role_id)
+)`
+	if stmt != expected {
+		t.Fatalf("unexpected statement:\n%s", stmt)
+	}
+
+	if _, err := d.CreateTableStmt("empty", nil); err == nil {
+		t.Fatal("expected error of table without columns")
+	}
+}
+
+func TestAddIndexStmt(t *testing.T) {
+	d, err := New("mysql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := d.AddIndexStmt("users", Index{Name: "users_email_idx", Columns: []string{"email"}, Unique: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt != "CREATE UNIQUE INDEX users_email_idx ON users (email)" {
+		t.Fatalf("unexpected statement: %s", stmt)
+	}
+
+	stmt, err = d.DropIndexStmt("users", "users_email_idx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt != "DROP INDEX users_email_idx ON users" {
+		t.Fatalf("unexpected statement: %s", stmt)
+	}
+}
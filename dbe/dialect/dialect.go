@@ -3,16 +3,35 @@ package dialect
 import (
 	"fmt"
 	"reflect"
+	"time"
 )
 
 // Dialect defines set of operations that are speccific to different SQL dialects
 type Dialect interface {
 	Name() string
 	CreateStmt(string, string, string) (string, error)
+	CreateManyStmt(string, string, []string) (string, error)
+	UpsertStmt(string, string, string, []string, []string) (string, error)
 	UpdateStmt(string, string, string) (string, error)
 	DeleteStmt(string, string) (string, error)
 	CountStmt(string, string) (string, error)
+	SavepointStmt(string) (string, error)
+	RollbackToSavepointStmt(string) (string, error)
+	ReleaseSavepointStmt(string) (string, error)
+	LockStmt(string, time.Duration) (string, error)
+	UnlockStmt(string) (string, error)
+	CreateTableStmt(string, []Column) (string, error)
+	DropTableStmt(string) (string, error)
+	AddColumnStmt(string, Column) (string, error)
+	DropColumnStmt(string, string) (string, error)
+	RenameColumnStmt(string, string, string) (string, error)
+	AddIndexStmt(string, Index) (string, error)
+	DropIndexStmt(string, string) (string, error)
 	TranslateSQL(string) string
+	QuoteIdent(string) string
+	InsertReturnsID() bool
+	InsertManyReturnsIDs() bool
+	UpsertReturnsID() bool
 }
 
 // list of registered dialects
@@ -0,0 +1,41 @@
+package dialect
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockStmt(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		expected string
+	}{
+		{"postgres", `SELECT CASE WHEN pg_try_advisory_lock(hashtext('db''s\lock')) THEN 1 ELSE 0 END`},
+		{"mysql", `SELECT COALESCE(GET_LOCK('db''s\\lock', 5), 0)`},
+		{"sqlite3", `SELECT 1`},
+	}
+	for _, tt := range tests {
+		d, err := New(tt.dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt, err := d.LockStmt(`db's\lock`, 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stmt != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.dialect, tt.expected, stmt)
+		}
+	}
+
+	// GET_LOCK timeout is given in whole seconds
+	stmt, err := MySQL{}.LockStmt("lock", 500*time.Millisecond)
+	if err != nil || stmt != "SELECT COALESCE(GET_LOCK('lock', 1), 0)" {
+		t.Fatalf("expected timeout rounded up to 1 second, got %s, %v", stmt, err)
+	}
+
+	stmt, err = Common{}.LockStmt("lock", time.Second)
+	if err != nil || stmt != "SELECT 1" {
+		t.Fatalf("expected no lock, got %s, %v", stmt, err)
+	}
+}
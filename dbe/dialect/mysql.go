@@ -1,5 +1,12 @@
 package dialect
 
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
 func init() {
 	RegisterDialect("mysql", &MySQL{})
 }
@@ -13,3 +20,76 @@ type MySQL struct {
 func (m MySQL) Name() string {
 	return "mysql"
 }
+
+// UpsertStmt creates SQL INSERT ... ON DUPLICATE KEY UPDATE statement.
+// MySQL resolves conflicts using table unique keys so conflictColumns are ignored.
+// Id of updated row is exposed through LAST_INSERT_ID.
+func (m MySQL) UpsertStmt(tableName string, columns string, columnNames string, conflictColumns []string, updateColumns []string) (string, error) {
+	set := []string{}
+	for _, col := range updateColumns {
+		set = append(set, fmt.Sprintf("%s = VALUES(%s)", col, col))
+	}
+	set = append(set, "id = LAST_INSERT_ID(id)")
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s", tableName, columns, columnNames, strings.Join(set, ", "))
+	return query, nil
+}
+
+// QuoteIdent quotes identifier using backticks
+func (m MySQL) QuoteIdent(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+// LockStmt creates SQL statement which acquires named lock using GET_LOCK.
+// Timeout is rounded up to whole seconds.
+func (m MySQL) LockStmt(name string, timeout time.Duration) (string, error) {
+	query := fmt.Sprintf("SELECT COALESCE(GET_LOCK(%s, %d), 0)", m.quoteString(name), int(math.Ceil(timeout.Seconds())))
+	return query, nil
+}
+
+// UnlockStmt creates SQL statement which releases named lock using RELEASE_LOCK
+func (m MySQL) UnlockStmt(name string) (string, error) {
+	query := fmt.Sprintf("SELECT RELEASE_LOCK(%s)", m.quoteString(name))
+	return query, nil
+}
+
+// quoteString quotes string literal, backslash is escape character in MySQL strings
+func (m MySQL) quoteString(s string) string {
+	return quoteString(strings.Replace(s, `\`, `\\`, -1))
+}
+
+var mysqlTypes = columnTypes{
+	types: map[string]string{
+		"string":    "VARCHAR(%d)",
+		"text":      "TEXT",
+		"int":       "INT",
+		"bigint":    "BIGINT",
+		"bool":      "BOOLEAN",
+		"float":     "DOUBLE",
+		"decimal":   "DECIMAL",
+		"timestamp": "DATETIME",
+		"date":      "DATE",
+		"uuid":      "CHAR(36)",
+		"blob":      "BLOB",
+		"json":      "JSON",
+	},
+	serial: map[string]string{
+		"int":    "INT NOT NULL AUTO_INCREMENT PRIMARY KEY",
+		"bigint": "BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY",
+	},
+}
+
+// CreateTableStmt creates SQL CREATE TABLE statement using MySQL column types
+func (m MySQL) CreateTableStmt(tableName string, columns []Column) (string, error) {
+	return mysqlTypes.createTableStmt(tableName, columns)
+}
+
+// AddColumnStmt creates SQL statement which adds column using MySQL column types
+func (m MySQL) AddColumnStmt(tableName string, column Column) (string, error) {
+	return mysqlTypes.addColumnStmt(tableName, column)
+}
+
+// DropIndexStmt creates SQL DROP INDEX statement, MySQL indexes are scoped to table
+func (m MySQL) DropIndexStmt(tableName string, name string) (string, error) {
+	return fmt.Sprintf("DROP INDEX %s ON %s", name, tableName), nil
+}
@@ -0,0 +1,172 @@
+package dialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterDialect("postgres", &Postgres{})
+}
+
+// Postgres implements dialect speciffic to PostgreSQL DB
+type Postgres struct {
+	Common
+}
+
+// Name for current dialect
+func (p Postgres) Name() string {
+	return "postgres"
+}
+
+// CreateStmt creates SQL INSERT statement which returns id of inserted row
+func (p Postgres) CreateStmt(tableName string, columns string, columnNames string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING id", tableName, unqualify(tableName, columns), columnNames)
+	return query, nil
+}
+
+// CreateManyStmt creates SQL INSERT statement for multiple rows which returns ids of inserted rows
+func (p Postgres) CreateManyStmt(tableName string, columns string, values []string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s RETURNING id", tableName, unqualify(tableName, columns), strings.Join(values, ", "))
+	return query, nil
+}
+
+// UpsertStmt creates SQL INSERT ... ON CONFLICT DO UPDATE statement which returns id of affected row
+func (p Postgres) UpsertStmt(tableName string, columns string, columnNames string, conflictColumns []string, updateColumns []string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s RETURNING id", tableName, unqualify(tableName, columns), columnNames, onConflict(conflictColumns, updateColumns))
+	return query, nil
+}
+
+// UpdateStmt creates SQL UPDATE statement
+func (p Postgres) UpdateStmt(tableName string, columns string, where string) (string, error) {
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", tableName, unqualify(tableName, columns), where)
+	return query, nil
+}
+
+// InsertReturnsID reports that CreateStmt returns id of inserted row
+func (p Postgres) InsertReturnsID() bool {
+	return true
+}
+
+// InsertManyReturnsIDs reports that CreateManyStmt returns ids of inserted rows
+func (p Postgres) InsertManyReturnsIDs() bool {
+	return true
+}
+
+// UpsertReturnsID reports that UpsertStmt returns id of inserted or updated row
+func (p Postgres) UpsertReturnsID() bool {
+	return true
+}
+
+// TranslateSQL rewrites `?` placeholders to PostgreSQL `$n` placeholders.
+// Question marks inside quoted strings and identifiers are left untouched.
+func (p Postgres) TranslateSQL(sql string) string {
+	var sb strings.Builder
+	sb.Grow(len(sql) + 10)
+
+	n := 0
+	var quote rune
+	for _, r := range sql {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '?':
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// LockStmt creates SQL statement which tries to acquire advisory lock with key
+// derived from name. It does not wait, so caller should retry until timeout.
+func (p Postgres) LockStmt(name string, timeout time.Duration) (string, error) {
+	query := fmt.Sprintf("SELECT CASE WHEN pg_try_advisory_lock(hashtext(%s)) THEN 1 ELSE 0 END", quoteString(name))
+	return query, nil
+}
+
+// UnlockStmt creates SQL statement which releases advisory lock with key derived from name
+func (p Postgres) UnlockStmt(name string) (string, error) {
+	query := fmt.Sprintf("SELECT pg_advisory_unlock(hashtext(%s))", quoteString(name))
+	return query, nil
+}
+
+// onConflict creates ON CONFLICT clause shared by PostgreSQL and SQLite
+func onConflict(conflictColumns []string, updateColumns []string) string {
+	if len(conflictColumns) == 0 {
+		conflictColumns = []string{"id"}
+	}
+	if len(updateColumns) == 0 {
+		// DO NOTHING does not return conflicting row, so it is updated to itself
+		updateColumns = conflictColumns[:1]
+	}
+
+	set := []string{}
+	for _, col := range updateColumns {
+		set = append(set, fmt.Sprintf("%s = excluded.%s", col, col))
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflictColumns, ", "), strings.Join(set, ", "))
+}
+
+// unqualify removes table name prefix from column names
+// as PostgreSQL does not allow qualified columns in INSERT and SET lists.
+// Prefix is removed only when table name is a complete identifier,
+// e.g. `old_users.id` is kept for `users` table.
+func unqualify(tableName string, columns string) string {
+	prefix := tableName + "."
+	var sb strings.Builder
+	sb.Grow(len(columns))
+	for i := 0; i < len(columns); {
+		if strings.HasPrefix(columns[i:], prefix) && (i == 0 || !isIdentByte(columns[i-1])) {
+			i += len(prefix)
+			continue
+		}
+		sb.WriteByte(columns[i])
+		i++
+	}
+	return sb.String()
+}
+
+// isIdentByte reports whether b may be part of a qualified identifier.
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '.' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+var postgresTypes = columnTypes{
+	types: map[string]string{
+		"string":    "VARCHAR(%d)",
+		"text":      "TEXT",
+		"int":       "INTEGER",
+		"bigint":    "BIGINT",
+		"bool":      "BOOLEAN",
+		"float":     "DOUBLE PRECISION",
+		"decimal":   "NUMERIC",
+		"timestamp": "TIMESTAMP",
+		"date":      "DATE",
+		"uuid":      "UUID",
+		"blob":      "BYTEA",
+		"json":      "JSONB",
+	},
+	serial: map[string]string{
+		"int":    "SERIAL PRIMARY KEY",
+		"bigint": "BIGSERIAL PRIMARY KEY",
+	},
+}
+
+// CreateTableStmt creates SQL CREATE TABLE statement using PostgreSQL column types
+func (p Postgres) CreateTableStmt(tableName string, columns []Column) (string, error) {
+	return postgresTypes.createTableStmt(tableName, columns)
+}
+
+// AddColumnStmt creates SQL statement which adds column using PostgreSQL column types
+func (p Postgres) AddColumnStmt(tableName string, column Column) (string, error) {
+	return postgresTypes.addColumnStmt(tableName, column)
+}
@@ -0,0 +1,37 @@
+package dialect
+
+import "testing"
+
+func TestPostgres_TranslateSQL(t *testing.T) {
+	p := Postgres{}
+
+	sql := p.TranslateSQL(`SELECT * FROM users WHERE name = ? AND note <> '?' AND "a?" = ?`)
+	expected := `SELECT * FROM users WHERE name = $1 AND note <> '?' AND "a?" = $2`
+	if sql != expected {
+		t.Fatalf("expected %s, got %s", expected, sql)
+	}
+}
+
+func TestPostgres_UpdateStmt(t *testing.T) {
+	p := Postgres{}
+
+	tests := []struct {
+		table    string
+		columns  string
+		expected string
+	}{
+		{"users", "users.name = :name, users.email = :email", "UPDATE users SET name = :name, email = :email WHERE id = 1"},
+		{"users", "old_users.id = :id,users.name = :name", "UPDATE users SET old_users.id = :id,name = :name WHERE id = 1"},
+		{"public.users", "public.users.name = :name", "UPDATE public.users SET name = :name WHERE id = 1"},
+		{"users", "xusers.id = :id, \"users\".name = :name", "UPDATE users SET xusers.id = :id, \"users\".name = :name WHERE id = 1"},
+	}
+	for _, tt := range tests {
+		stmt, err := p.UpdateStmt(tt.table, tt.columns, "id = 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stmt != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, stmt)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterDialect("sqlite3", &SQLite{})
+}
+
+// SQLite implements dialect speciffic to SQLite DB
+type SQLite struct {
+	Common
+}
+
+// Name for current dialect
+func (s SQLite) Name() string {
+	return "sqlite3"
+}
+
+// CreateStmt creates SQL INSERT statement
+func (s SQLite) CreateStmt(tableName string, columns string, columnNames string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, unqualify(tableName, columns), columnNames)
+	return query, nil
+}
+
+// CreateManyStmt creates SQL INSERT statement for multiple rows which returns ids of inserted rows
+func (s SQLite) CreateManyStmt(tableName string, columns string, values []string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s RETURNING id", tableName, unqualify(tableName, columns), strings.Join(values, ", "))
+	return query, nil
+}
+
+// UpsertStmt creates SQL INSERT ... ON CONFLICT DO UPDATE statement which returns id of affected row
+func (s SQLite) UpsertStmt(tableName string, columns string, columnNames string, conflictColumns []string, updateColumns []string) (string, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s RETURNING id", tableName, unqualify(tableName, columns), columnNames, onConflict(conflictColumns, updateColumns))
+	return query, nil
+}
+
+// UpdateStmt creates SQL UPDATE statement
+func (s SQLite) UpdateStmt(tableName string, columns string, where string) (string, error) {
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", tableName, unqualify(tableName, columns), where)
+	return query, nil
+}
+
+// InsertManyReturnsIDs reports that CreateManyStmt returns ids of inserted rows
+func (s SQLite) InsertManyReturnsIDs() bool {
+	return true
+}
+
+// UpsertReturnsID reports that UpsertStmt returns id of inserted or updated row
+func (s SQLite) UpsertReturnsID() bool {
+	return true
+}
+
+// LockStmt creates SQL statement which always acquires the lock,
+// as SQLite serializes writers using database file lock
+func (s SQLite) LockStmt(name string, timeout time.Duration) (string, error) {
+	return "SELECT 1", nil
+}
+
+// UnlockStmt creates SQL statement which releases the lock
+func (s SQLite) UnlockStmt(name string) (string, error) {
+	return "SELECT 1", nil
+}
+
+var sqliteTypes = columnTypes{
+	types: map[string]string{
+		"string":    "VARCHAR(%d)",
+		"text":      "TEXT",
+		"int":       "INTEGER",
+		"bigint":    "INTEGER",
+		"bool":      "BOOLEAN",
+		"float":     "REAL",
+		"decimal":   "NUMERIC",
+		"timestamp": "DATETIME",
+		"date":      "DATE",
+		"uuid":      "TEXT",
+		"blob":      "BLOB",
+		"json":      "TEXT",
+	},
+	serial: map[string]string{
+		"int":    "INTEGER PRIMARY KEY AUTOINCREMENT",
+		"bigint": "INTEGER PRIMARY KEY AUTOINCREMENT",
+	},
+}
+
+// CreateTableStmt creates SQL CREATE TABLE statement using SQLite column types
+func (s SQLite) CreateTableStmt(tableName string, columns []Column) (string, error) {
+	return sqliteTypes.createTableStmt(tableName, columns)
+}
+
+// AddColumnStmt creates SQL statement which adds column using SQLite column types
+func (s SQLite) AddColumnStmt(tableName string, column Column) (string, error) {
+	return sqliteTypes.addColumnStmt(tableName, column)
+}
@@ -1,6 +1,8 @@
 package dbe
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -9,7 +11,11 @@ import (
 
 // Select executes provided query and maps result to provided model object
 func (c *Connection) Select(model interface{}, sql string, args ...string) error {
-	return c.Store.Select(model, sql, args)
+	params := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		params = append(params, arg)
+	}
+	return c.Store.SelectContext(c.Context(), model, sql, params...)
 }
 
 // Create add a new given entry to the database, excluding the given columns.
@@ -28,31 +34,75 @@ func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
 	cols := m.Columns()
 	cols.Remove(excludeColumns...)
 
+	// let database generate id when it is not set
+	if fmt.Sprint(m.ID()) == "0" {
+		cols.Remove("id")
+	}
+
 	stmt, err := c.Dialect.CreateStmt(m.TableName(), cols.String(), cols.ParamString())
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	Logger.Info(stmt)
-
-	res, err := c.Store.NamedExec(stmt, m.Value)
-	if err != nil {
-		return errors.WithStack(err)
+	if err := c.insert(m, stmt, c.Dialect.InsertReturnsID()); err != nil {
+		return err
 	}
 
-	id, err := res.LastInsertId()
+	return m.afterCreate(c)
+}
 
-	if err != nil {
-		return errors.WithStack(err)
+// insert executes INSERT statement for given model and sets id to it,
+// which is read from statement result when it returns id of affected row
+func (c *Connection) insert(m *Model, stmt string, returnsID bool) error {
+	var id interface{}
+	if returnsID {
+		ns, err := c.Store.PrepareNamedContext(c.Context(), stmt)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer ns.Close()
+
+		_, err = c.instrument(stmt, m.Value, func(ctx context.Context) (sql.Result, error) {
+			return nil, ns.QueryRowContext(ctx, m.Value).Scan(&id)
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return errors.WithStack(err)
+		}
+	} else {
+		res, err := c.Store.NamedExecContext(c.Context(), stmt, m.Value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		lastID, err := res.LastInsertId()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		id = lastID
 	}
-	m.setID(id)
 
-	return m.afterCreate(c)
+	// keep id provided by model when database does not return one
+	if id != nil && fmt.Sprint(id) != "0" {
+		m.setID(id)
+	}
+	return nil
 }
 
-// Delete given model from database
+// Delete given model from database.
+// Models with `DeletedAt` field of `*time.Time` or `nulls.Time` type
+// are soft deleted, see `HardDelete` to permanently remove them.
 func (c *Connection) Delete(model interface{}) error {
 	m := &Model{Value: model}
+	if m.softDeletable() {
+		return c.softDelete(m)
+	}
+	return c.HardDelete(model)
+}
+
+// HardDelete permanently removes given model from database,
+// even when it supports soft deletes.
+func (c *Connection) HardDelete(model interface{}) error {
+	m := &Model{Value: model}
 
 	if err := m.beforeDelete(c); err != nil {
 		return err
@@ -64,9 +114,7 @@ func (c *Connection) Delete(model interface{}) error {
 		return errors.WithStack(err)
 	}
 
-	Logger.Info(stmt)
-
-	_, err = c.Store.Exec(stmt)
+	_, err = c.Store.ExecContext(c.Context(), stmt)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -89,36 +137,75 @@ func (c *Connection) Save(model interface{}, excludeColumns ...string) error {
 
 }
 
-// Update given model to database
+// ErrStaleObject is returned by Update when model uses optimistic locking
+// and its row was changed or deleted since the model was loaded.
+//
+//	if _, ok := errors.Cause(err).(dbe.ErrStaleObject); ok {
+//		// respond with 409 Conflict
+//	}
+type ErrStaleObject struct {
+	Table string
+	ID    interface{}
+}
+
+func (e ErrStaleObject) Error() string {
+	return fmt.Sprintf("stale object: %s with id %v was modified or deleted", e.Table, e.ID)
+}
+
+// Update given model to database.
+// Models with `Version` or `LockVersion` field use optimistic locking,
+// update succeeds only if version in database matches model version,
+// otherwise `ErrStaleObject` is returned.
 func (c *Connection) Update(model interface{}, excludeColumns ...string) error {
 	m := &Model{Value: model}
 	if err := m.beforeUpdate(c); err != nil {
 		return err
 	}
 
-	cols := m.Columns()
-	cols.Remove("id", "created_at")
-	cols.Remove(excludeColumns...)
-
 	m.TouchUpdatedAt()
 
-	stmt, err := c.Dialect.UpdateStmt(m.TableName(), cols.UpdateString(), m.WhereID())
+	stmt, err := c.updateStmt(m, m.WhereID(), excludeColumns)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	Logger.Info(stmt)
-
-	_, err = c.Store.NamedExec(stmt, m.Value)
+	res, err := c.Store.NamedExecContext(c.Context(), stmt, m.Value)
 
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	if err := m.checkLockVersion(res); err != nil {
+		return err
+	}
+	m.incrementLockVersion()
+
 	return m.afterUpdate(c)
 }
 
+// updateStmt creates UPDATE statement for given model, where condition
+// is extended with version check for models using optimistic locking
+func (c *Connection) updateStmt(m *Model, where string, excludeColumns []string) (string, error) {
+	cols := m.Columns()
+	cols.Remove("id", "created_at")
+	cols.Remove(excludeColumns...)
+
+	set := cols.UpdateString()
+
+	if lock := m.lockColumn(); lock != "" {
+		cols.Remove(lock)
+		set = cols.UpdateString()
+		if set != "" {
+			set += ", "
+		}
+		set += fmt.Sprintf("%s = %s + 1", lock, lock)
+		where = fmt.Sprintf("%s AND %s.%s = :%s", where, m.TableName(), lock, lock)
+	}
+
+	return c.Dialect.UpdateStmt(m.TableName(), set, where)
+}
+
 // ValidateAndCreate applies validation rules on the given entry, then creates it
 // if the validation succeed, excluding the given columns.
 func (c *Connection) ValidateAndCreate(model interface{}, excludeColumns ...string) (*validate.Errors, error) {
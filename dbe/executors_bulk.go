@@ -0,0 +1,326 @@
+package dbe
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// CreateManyBatchSize is the maximum number of rows inserted by a single statement
+var CreateManyBatchSize = 100
+
+// CreateMany adds given slice of entries to the database using multi-row
+// INSERT statements, excluding the given columns. It updates `created_at`
+// and `updated_at` columns and calls create callbacks for every entry.
+// Generated ids are set to entries before create callbacks are called.
+// Dialects which can't return ids of multiple inserted rows, e.g. MySQL,
+// insert entries one by one when ids are generated by database.
+//
+//	c.CreateMany(&[]User{{Name: "Irfan"}, {Name: "Sedin"}})
+func (c *Connection) CreateMany(models interface{}, excludeColumns ...string) error {
+	ms, err := modelsOf(models)
+	if err != nil {
+		return err
+	}
+	if len(ms) == 0 {
+		return nil
+	}
+
+	for _, m := range ms {
+		if err := m.beforeCreate(c); err != nil {
+			return err
+		}
+		m.TouchCreatedAt()
+		m.TouchUpdatedAt()
+	}
+
+	cols := ms[0].Columns()
+	cols.Remove(excludeColumns...)
+
+	// let database generate ids when they are not set
+	if fmt.Sprint(ms[0].ID()) == "0" {
+		cols.Remove("id")
+	}
+
+	err = c.Transaction(func(tx *Connection) error {
+		for start := 0; start < len(ms); start += CreateManyBatchSize {
+			end := start + CreateManyBatchSize
+			if end > len(ms) {
+				end = len(ms)
+			}
+
+			if err := tx.createBatch(ms[start:end], cols); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		if err := m.afterCreate(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBatch inserts given models using single INSERT statement
+func (c *Connection) createBatch(ms []*Model, cols Columns) error {
+	if _, err := ms[0].fieldByName("ID"); err == nil && cols.Cols["id"] == nil && !c.Dialect.InsertManyReturnsIDs() {
+		return c.createEach(ms, cols)
+	}
+
+	values := []string{}
+	args := []interface{}{}
+	for _, m := range ms {
+		v, a, err := sqlx.Named(fmt.Sprintf("(%s)", cols.ParamString()), m.Value)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		values = append(values, v)
+		args = append(args, a...)
+	}
+
+	stmt, err := c.Dialect.CreateManyStmt(ms[0].TableName(), cols.String(), values)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	stmt = c.Dialect.TranslateSQL(stmt)
+
+	idField, err := ms[0].fieldByName("ID")
+	if err != nil || cols.Cols["id"] != nil {
+		// ids are not generated by database
+		_, err = c.Store.ExecContext(c.Context(), stmt, args...)
+		return errors.WithStack(err)
+	}
+
+	ids := reflect.New(reflect.SliceOf(idField.Type()))
+	err = c.Store.SelectContext(c.Context(), ids.Interface(), stmt, args...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if ids.Elem().Len() != len(ms) {
+		return errors.Errorf("expected %d ids of inserted rows, got %d", len(ms), ids.Elem().Len())
+	}
+
+	for i := range ms {
+		ms[i].setID(ids.Elem().Index(i).Interface())
+	}
+	return nil
+}
+
+// createEach inserts given models one by one reading generated id of every
+// row, as driver reports id of the first row inserted by multi-row INSERT
+// only and rows don't get consecutive ids with every auto increment setting
+func (c *Connection) createEach(ms []*Model, cols Columns) error {
+	stmt, err := c.Dialect.CreateStmt(ms[0].TableName(), cols.String(), cols.ParamString())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, m := range ms {
+		if err := c.insert(m, stmt, c.Dialect.InsertReturnsID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upsert adds given entry to the database or updates existing entry
+// when it conflicts on given columns (id by default). MySQL resolves
+// conflicts using table unique keys. It updates `created_at` and `updated_at`
+// columns automatically, `created_at` of existing entries is not changed
+// and is read back into the entry.
+// Create callbacks are called for the entry.
+//
+//	c.Upsert(&User{Email: "irfan@example.com", Name: "Irfan"}, "email")
+func (c *Connection) Upsert(model interface{}, conflictColumns ...string) error {
+	m := &Model{Value: model}
+
+	if err := m.beforeCreate(c); err != nil {
+		return err
+	}
+
+	m.TouchCreatedAt()
+	m.TouchUpdatedAt()
+
+	cols := m.Columns()
+
+	// let database generate id when it is not set
+	if fmt.Sprint(m.ID()) == "0" {
+		cols.Remove("id")
+	}
+
+	conflict := map[string]bool{"id": true, "created_at": true}
+	for _, col := range conflictColumns {
+		conflict[col] = true
+	}
+	updateColumns := []string{}
+	for name := range cols.Cols {
+		if !conflict[name] {
+			updateColumns = append(updateColumns, name)
+		}
+	}
+	sort.Strings(updateColumns)
+
+	stmt, err := c.Dialect.UpsertStmt(m.TableName(), cols.String(), cols.ParamString(), conflictColumns, updateColumns)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := c.insert(m, stmt, c.Dialect.UpsertReturnsID()); err != nil {
+		return err
+	}
+
+	// driver may not report id of existing row which was updated
+	if err := c.reloadID(m, conflictColumns); err != nil {
+		return err
+	}
+
+	if err := c.reloadCreatedAt(m); err != nil {
+		return err
+	}
+
+	return m.afterCreate(c)
+}
+
+// reloadID reads id of upserted entry by its conflict columns
+// when id was not returned by database
+func (c *Connection) reloadID(m *Model, conflictColumns []string) error {
+	fbn, err := m.fieldByName("ID")
+	if err != nil || fmt.Sprint(m.ID()) != "0" || len(conflictColumns) == 0 {
+		return nil
+	}
+
+	where := []string{}
+	for _, col := range conflictColumns {
+		where = append(where, fmt.Sprintf("%s = :%s", col, col))
+	}
+	query := fmt.Sprintf("SELECT id FROM %s WHERE %s", m.TableName(), strings.Join(where, " AND "))
+
+	ns, err := c.Store.PrepareNamedContext(c.Context(), query)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer ns.Close()
+
+	_, err = c.instrument(query, m.Value, func(ctx context.Context) (sql.Result, error) {
+		return nil, ns.GetContext(ctx, fbn.Addr().Interface(), m.Value)
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// reloadCreatedAt reads `created_at` of upserted entry, which keeps its
+// original value when existing entry was updated instead of inserted
+func (c *Connection) reloadCreatedAt(m *Model) error {
+	fbn, err := m.fieldByName("CreatedAt")
+	if err != nil || fmt.Sprint(m.ID()) == "0" {
+		return nil
+	}
+
+	query := fmt.Sprintf("SELECT created_at FROM %s WHERE %s", m.TableName(), m.WhereID())
+	err = c.Store.GetContext(c.Context(), fbn.Addr().Interface(), c.Dialect.TranslateSQL(query))
+	if err != nil && err != sql.ErrNoRows {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// UpdateMany updates given slice of entries in the database, excluding
+// the given columns, using single prepared statement within a transaction.
+// It updates `updated_at` column and calls update callbacks for every entry.
+// Transaction is rolled back with `ErrStaleObject` when any of entries using
+// optimistic locking is stale.
+//
+//	c.UpdateMany(&users)
+func (c *Connection) UpdateMany(models interface{}, excludeColumns ...string) error {
+	ms, err := modelsOf(models)
+	if err != nil {
+		return err
+	}
+	if len(ms) == 0 {
+		return nil
+	}
+
+	for _, m := range ms {
+		if err := m.beforeUpdate(c); err != nil {
+			return err
+		}
+		m.TouchUpdatedAt()
+	}
+
+	stmt, err := c.updateStmt(ms[0], fmt.Sprintf("%s.id = :id", ms[0].TableName()), excludeColumns)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = c.Transaction(func(tx *Connection) error {
+		return tx.updateBatch(ms, stmt)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		m.incrementLockVersion()
+	}
+
+	for _, m := range ms {
+		if err := m.afterUpdate(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateBatch executes prepared UPDATE statement for every model
+func (c *Connection) updateBatch(ms []*Model, stmt string) error {
+	ns, err := c.Store.PrepareNamedContext(c.Context(), stmt)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer ns.Close()
+
+	for _, m := range ms {
+		res, err := c.instrument(stmt, m.Value, func(ctx context.Context) (sql.Result, error) {
+			return ns.ExecContext(ctx, m.Value)
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := m.checkLockVersion(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modelsOf wraps every element of given slice into Model
+func modelsOf(models interface{}) ([]*Model, error) {
+	v := reflect.Indirect(reflect.ValueOf(models))
+	if v.Kind() != reflect.Slice {
+		return nil, errors.Errorf("expected slice of models, got %T", models)
+	}
+
+	ms := make([]*Model, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.Kind() != reflect.Ptr {
+			e = e.Addr()
+		}
+		ms = append(ms, &Model{Value: e.Interface()})
+	}
+	return ms, nil
+}
@@ -0,0 +1,172 @@
+package dbe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sedind/flow/dbe/dialect"
+)
+
+func TestConnection_CreateMany(t *testing.T) {
+	c := newTestConnection(t)
+
+	defer func(size int) { CreateManyBatchSize = size }(CreateManyBatchSize)
+	CreateManyBatchSize = 2
+
+	users := []testUser{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+	for i, u := range users {
+		if u.ID != i+1 {
+			t.Fatalf("expected id %d of %s, got %d", i+1, u.Name, u.ID)
+		}
+	}
+
+	n, err := c.Query().Count(&testUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 users, got %d", n)
+	}
+}
+
+// lastInsertIDDialect is SQLite dialect relying on driver LastInsertId
+// like MySQL dialect does
+type lastInsertIDDialect struct {
+	dialect.SQLite
+}
+
+func (lastInsertIDDialect) InsertManyReturnsIDs() bool { return false }
+
+func TestConnection_CreateManyLastInsertID(t *testing.T) {
+	c := newTestConnection(t)
+	c.Dialect = lastInsertIDDialect{}
+
+	users := []testUser{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+	for i, u := range users {
+		if u.ID != i+1 {
+			t.Fatalf("expected id %d of %s, got %d", i+1, u.Name, u.ID)
+		}
+	}
+}
+
+func TestConnection_UpdateMany(t *testing.T) {
+	c := newTestConnection(t)
+
+	users := []testUser{{Name: "a"}, {Name: "b"}}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+	for i := range users {
+		users[i].Name += "!"
+	}
+	if err := c.UpdateMany(users); err != nil {
+		t.Fatal(err)
+	}
+
+	found := []testUser{}
+	if err := c.Query().Order("id").All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if found[0].Name != "a!" || found[1].Name != "b!" {
+		t.Fatalf("expected updated names, got %+v", found)
+	}
+}
+
+func TestConnection_Upsert(t *testing.T) {
+	c := newTestConnection(t)
+
+	u := &testUser{Name: "a"}
+	if err := c.Create(u); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &testUser{ID: u.ID, Name: "b"}
+	if err := c.Upsert(updated); err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CreatedAt.Equal(u.CreatedAt) {
+		t.Fatalf("expected created_at %s of existing user, got %s", u.CreatedAt, updated.CreatedAt)
+	}
+	found := testUser{}
+	if err := c.Query().Find(&found, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "b" {
+		t.Fatalf("expected name b, got %s", found.Name)
+	}
+
+	inserted := &testUser{Name: "c"}
+	if err := c.Upsert(inserted); err != nil {
+		t.Fatal(err)
+	}
+	if inserted.ID != 2 {
+		t.Fatalf("expected id 2 of inserted user, got %d", inserted.ID)
+	}
+}
+
+// testAccount is model of accounts table with unique email column
+type testAccount struct {
+	ID        int       `db:"id"`
+	Email     string    `db:"email"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (testAccount) TableName() string { return "accounts" }
+
+func TestConnection_UpsertConflictColumn(t *testing.T) {
+	c := newTestConnection(t,
+		"CREATE TABLE accounts (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT NOT NULL UNIQUE, name TEXT NOT NULL, created_at DATETIME, updated_at DATETIME)",
+	)
+
+	existing := &testAccount{Email: "a@example.com", Name: "a"}
+	if err := c.Create(existing); err != nil {
+		t.Fatal(err)
+	}
+	// last inserted row is not the one updated by upsert
+	if err := c.Create(&testAccount{Email: "b@example.com", Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &testAccount{Email: "a@example.com", Name: "updated"}
+	if err := c.Upsert(updated, "email"); err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != existing.ID {
+		t.Fatalf("expected id %d of existing account, got %d", existing.ID, updated.ID)
+	}
+	if !updated.CreatedAt.Equal(existing.CreatedAt) {
+		t.Fatalf("expected created_at %s of existing account, got %s", existing.CreatedAt, updated.CreatedAt)
+	}
+
+	found := testAccount{}
+	if err := c.Query().Find(&found, existing.ID); err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "updated" {
+		t.Fatalf("expected name updated, got %s", found.Name)
+	}
+
+	// entry without update columns returns id of existing row
+	if _, err := c.Store.Exec("CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE)"); err != nil {
+		t.Fatal(err)
+	}
+	tags := []testTag{{Name: "x"}, {Name: "y"}}
+	if err := c.CreateMany(&tags); err != nil {
+		t.Fatal(err)
+	}
+	tag := &testTag{Name: "x"}
+	if err := c.Upsert(tag, "name"); err != nil {
+		t.Fatal(err)
+	}
+	if tag.ID != tags[0].ID {
+		t.Fatalf("expected id %d of existing tag, got %d", tags[0].ID, tag.ID)
+	}
+}
@@ -0,0 +1,183 @@
+package dbe
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// testDocument is model using optimistic locking
+type testDocument struct {
+	ID      int    `db:"id"`
+	Title   string `db:"title"`
+	Version int    `db:"version"`
+}
+
+func (testDocument) TableName() string { return "documents" }
+
+const createDocuments = "CREATE TABLE documents (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT NOT NULL, version INTEGER NOT NULL)"
+
+func TestConnection_Create(t *testing.T) {
+	c := newTestConnection(t)
+
+	u := &testUser{Name: "Irfan"}
+	if err := c.Create(u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 1 {
+		t.Fatalf("expected id 1, got %d", u.ID)
+	}
+	if u.CreatedAt.IsZero() || u.UpdatedAt.IsZero() {
+		t.Fatalf("expected timestamps to be set, got %+v", u)
+	}
+
+	found := testUser{}
+	if err := c.Query().Find(&found, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "Irfan" {
+		t.Fatalf("expected Irfan, got %s", found.Name)
+	}
+}
+
+func TestConnection_Update(t *testing.T) {
+	c := newTestConnection(t)
+
+	u := &testUser{Name: "Irfan"}
+	if err := c.Create(u); err != nil {
+		t.Fatal(err)
+	}
+	createdAt := u.CreatedAt
+
+	u.Name = "Sedin"
+	if err := c.Update(u); err != nil {
+		t.Fatal(err)
+	}
+	if !u.CreatedAt.Equal(createdAt) {
+		t.Fatalf("expected created_at %s to be kept, got %s", createdAt, u.CreatedAt)
+	}
+
+	found := testUser{}
+	if err := c.Query().Find(&found, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if found.Name != "Sedin" {
+		t.Fatalf("expected Sedin, got %s", found.Name)
+	}
+}
+
+func TestConnection_Delete(t *testing.T) {
+	c := newTestConnection(t)
+
+	u := &testUser{Name: "Irfan"}
+	if err := c.Create(u); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(u); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.Query().Find(&testUser{}, u.ID)
+	if errors.Cause(err) != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestConnection_OptimisticLocking(t *testing.T) {
+	c := newTestConnection(t, createDocuments)
+
+	p := &testDocument{Title: "draft"}
+	if err := c.Create(p); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := testDocument{}, testDocument{}
+	if err := c.Query().Find(&first, p.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Query().Find(&second, p.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	first.Title = "first"
+	if err := c.Update(&first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != 1 {
+		t.Fatalf("expected version 1, got %d", first.Version)
+	}
+
+	second.Title = "second"
+	err := c.Update(&second)
+	if _, ok := errors.Cause(err).(ErrStaleObject); !ok {
+		t.Fatalf("expected ErrStaleObject, got %v", err)
+	}
+
+	if err := c.UpdateMany([]*testDocument{&first}); err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != 2 {
+		t.Fatalf("expected version 2, got %d", first.Version)
+	}
+	err = c.UpdateMany([]*testDocument{&second})
+	if _, ok := errors.Cause(err).(ErrStaleObject); !ok {
+		t.Fatalf("expected ErrStaleObject, got %v", err)
+	}
+}
+
+// testNote has version field of type which can't be used for optimistic locking
+type testNote struct {
+	ID          int    `db:"id"`
+	Title       string `db:"title"`
+	LockVersion string `db:"lock_version"`
+}
+
+func (testNote) TableName() string { return "notes" }
+
+// testMemo has version field which is not mapped to a column
+type testMemo struct {
+	ID      int    `db:"id"`
+	Title   string `db:"title"`
+	Version int    `db:"-"`
+}
+
+func (testMemo) TableName() string { return "notes" }
+
+func TestConnection_UpdateWithoutLockColumn(t *testing.T) {
+	c := newTestConnection(t, "CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT NOT NULL, lock_version TEXT NOT NULL DEFAULT 'a')")
+
+	n := &testNote{Title: "draft", LockVersion: "a"}
+	if err := c.Create(n); err != nil {
+		t.Fatal(err)
+	}
+	n.Title = "final"
+	if err := c.Update(n); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateMany([]*testNote{n}); err != nil {
+		t.Fatal(err)
+	}
+
+	found := testNote{}
+	if err := c.Query().Find(&found, n.ID); err != nil {
+		t.Fatal(err)
+	}
+	if found.Title != "final" || found.LockVersion != "a" {
+		t.Fatalf("expected title final and lock version a, got %+v", found)
+	}
+
+	m := &testMemo{Title: "draft", Version: 7}
+	if err := c.Create(m); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(m); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateMany([]*testMemo{m}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != 7 {
+		t.Fatalf("expected version 7 to be kept, got %d", m.Version)
+	}
+}
@@ -3,20 +3,25 @@ package dbe
 import (
 	"bytes"
 	"fmt"
-	"html/template"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/pkg/errors"
 )
 
-// FileMigrator is a migrator for SQL
-// files on disk at a specified path.
+// FileMigrator is a migrator for SQL and fizz
+// files on disk at a specified path or in a file system.
 type FileMigrator struct {
 	Migrator
 	Path string
+	// FS holding migration files, it is rooted at Path for migrators
+	// created with NewFileMigrator
+	FS fs.FS
 }
 
 // NewFileMigrator for a path and a Connection
@@ -28,6 +33,11 @@ func NewFileMigrator(path string, conn *Connection) (FileMigrator, error) {
 
 	fm.SchemaPath = path
 
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		return fm, nil
+	}
+	fm.FS = os.DirFS(path)
+
 	err := fm.loadMigrations()
 	if err != nil {
 		return fm, errors.WithStack(err)
@@ -36,15 +46,36 @@ func NewFileMigrator(path string, conn *Connection) (FileMigrator, error) {
 	return fm, nil
 }
 
-func (fm *FileMigrator) loadMigrations() error {
-	dir := fm.Path
-	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
-		return nil
+// NewFSMigrator for a file system and a Connection. It allows running
+// migrations embedded into application binary.
+//
+//	//go:embed migrations
+//	var migrations embed.FS
+//
+//	fsys, _ := fs.Sub(migrations, "migrations")
+//	fm, err := dbe.NewFSMigrator(fsys, conn)
+func NewFSMigrator(fsys fs.FS, conn *Connection) (FileMigrator, error) {
+	fm := FileMigrator{
+		Migrator: NewMigrator(conn),
+		FS:       fsys,
+	}
+
+	err := fm.loadMigrations()
+	if err != nil {
+		return fm, errors.WithStack(err)
 	}
 
-	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
-		if !info.IsDir() {
-			matches := migrationRegEx.FindAllStringSubmatch(info.Name(), -1)
+	return fm, nil
+}
+
+func (fm *FileMigrator) loadMigrations() error {
+	fsys := fm.FS
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			matches := migrationRegEx.FindAllStringSubmatch(d.Name(), -1)
 			if matches == nil || len(matches) == 0 {
 				return nil
 			}
@@ -53,17 +84,16 @@ func (fm *FileMigrator) loadMigrations() error {
 			dir := match[4]
 
 			migration := Migration{
-				Path:      p,
+				Path:      filepath.Join(fm.Path, filepath.FromSlash(p)),
 				Version:   match[1],
 				Name:      match[2],
 				Direction: dir,
+				Type:      match[5],
+				Content: func(migration Migration) ([]byte, error) {
+					return fs.ReadFile(fsys, p)
+				},
 				Runner: func(migration Migration, conn *Connection) error {
-					f, err := os.Open(p)
-					if err != nil {
-						return errors.WithStack(err)
-					}
-
-					content, err := migrateContent(migration, f)
+					content, err := migration.SQL(conn)
 					if err != nil {
 						return errors.Wrapf(err, "error processing %s", migration.Path)
 					}
@@ -72,26 +102,15 @@ func (fm *FileMigrator) loadMigrations() error {
 						return nil
 					}
 
-					c, err := conn.NewTx()
-					if err != nil {
-						return err
-					}
-
-					defer func() {
-						if err != nil {
-							c.Tx.Rollback()
-							fmt.Printf("Migration %s Failed. Rolling back...", migration.Name)
-							return
-						}
-						c.Tx.Commit()
-					}()
-
-					_, err = c.Store.Exec(content)
-					if err != nil {
+					err = conn.Transaction(func(tx *Connection) error {
+						_, err := tx.Store.ExecContext(tx.Context(), content)
 						return errors.Wrapf(err, "error executing %s, sql: %s", migration.Path, content)
+					})
+					if err != nil {
+						fmt.Printf("Migration %s Failed. Rolling back...", migration.Name)
 					}
 
-					return nil
+					return err
 				}, // Runner end
 			} // Migration end
 
@@ -100,10 +119,43 @@ func (fm *FileMigrator) loadMigrations() error {
 		}
 		return nil
 	})
-	return nil
 }
 
-func migrateContent(m Migration, r io.Reader) (string, error) {
+// MigrationData is data passed to migration templates, so a single
+// migration can serve several environments
+//
+//	{{ if eq .Dialect "mysql" }}ENGINE=InnoDB{{ end }}
+//	INSERT INTO {{ quoteIdent "order" }} (currency) VALUES ('{{ env "CURRENCY" "EUR" }}');
+//	INSERT INTO settings (name) VALUES ('{{ .Settings.app_name }}');
+type MigrationData struct {
+	// Dialect name of migrated connection
+	Dialect string
+	// Database name of migrated connection
+	Database string
+	// Env holds environment variables
+	Env map[string]string
+	// Settings holds application settings set on Migrator
+	Settings map[string]string
+}
+
+// migrationFuncs returns helper functions available to migration templates:
+// env returns environment variable or optional default value when it is empty,
+// quoteIdent quotes identifier for conn dialect
+func migrationFuncs(conn *Connection) template.FuncMap {
+	return template.FuncMap{
+		"env": func(name string, def ...string) string {
+			if v := os.Getenv(name); v != "" || len(def) == 0 {
+				return v
+			}
+			return def[0]
+		},
+		"quoteIdent": conn.Dialect.QuoteIdent,
+	}
+}
+
+// migrateContent renders migration template, fizz migrations
+// are then translated into SQL of conn dialect
+func migrateContent(m Migration, conn *Connection, r io.Reader) (string, error) {
 	raw, err := ioutil.ReadAll(r)
 	if err != nil {
 		return "", errors.WithStack(err)
@@ -111,17 +163,41 @@ func migrateContent(m Migration, r io.Reader) (string, error) {
 
 	content := string(raw)
 
-	temp := template.Must(template.New("sql").Parse(content))
+	temp, err := template.New("sql").Funcs(migrationFuncs(conn)).Parse(content)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not parse migration template %s", m.Path)
+	}
+
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if i := strings.Index(kv, "="); i > 0 {
+			env[kv[:i]] = kv[i+1:]
+		}
+	}
+
+	data := MigrationData{
+		Dialect:  conn.Dialect.Name(),
+		Database: conn.Details.Database,
+		Env:      env,
+		Settings: m.settings,
+	}
 
 	var buff bytes.Buffer
 
-	err = temp.Execute(&buff, nil)
+	err = temp.Execute(&buff, data)
 	if err != nil {
 		return "", errors.Wrapf(err, "could not execute migration template %s", m.Path)
 	}
 
 	content = buff.String()
 
+	if m.Type == "fizz" {
+		content, err = fizzToSQL(content, conn.Dialect)
+		if err != nil {
+			return "", errors.Wrapf(err, "could not translate fizz migration %s", m.Path)
+		}
+	}
+
 	return content, nil
 
 }
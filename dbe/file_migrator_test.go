@@ -0,0 +1,158 @@
+package dbe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// testMigrationFiles holds three migrations creating t1, t2 and t3 tables
+func testMigrationFiles() fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for i := 1; i <= 3; i++ {
+		name := fmt.Sprintf("2020010100000%d_t%d", i, i)
+		fsys[name+".up.sql"] = &fstest.MapFile{Data: []byte(fmt.Sprintf("CREATE TABLE t%d (id INTEGER);", i))}
+		fsys[name+".down.sql"] = &fstest.MapFile{Data: []byte(fmt.Sprintf("DROP TABLE t%d;", i))}
+	}
+	fsys["README.md"] = &fstest.MapFile{Data: []byte("not a migration")}
+	return fsys
+}
+
+// newTestMigrator creates migrator of migrations from testMigrationFiles
+func newTestMigrator(t *testing.T) (FileMigrator, *Connection) {
+	t.Helper()
+
+	c := newTestConnection(t)
+	fm, err := NewFSMigrator(testMigrationFiles(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fm, c
+}
+
+func TestNewFSMigrator(t *testing.T) {
+	fm, c := newTestMigrator(t)
+
+	if len(fm.Migrations["up"]) != 3 || len(fm.Migrations["down"]) != 3 {
+		t.Fatalf("expected 3 up and down migrations, got %d and %d", len(fm.Migrations["up"]), len(fm.Migrations["down"]))
+	}
+
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if v := appliedVersions(t, fm.Migrator); len(v) != 3 {
+		t.Fatalf("expected 3 applied migrations, got %v", v)
+	}
+	for _, table := range []string{"t1", "t2", "t3"} {
+		if _, err := c.Store.Exec("SELECT * FROM " + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := fm.Down(2); err != nil {
+		t.Fatal(err)
+	}
+	if v := appliedVersions(t, fm.Migrator); fmt.Sprint(v) != "[20200101000001]" {
+		t.Fatalf("expected only first migration to be applied, got %v", v)
+	}
+	if _, err := c.Store.Exec("SELECT * FROM t2"); err == nil {
+		t.Fatal("expected t2 table to be dropped")
+	}
+}
+
+func TestNewFileMigrator(t *testing.T) {
+	dir := t.TempDir()
+	for name, f := range testMigrationFiles() {
+		if err := os.WriteFile(filepath.Join(dir, name), f.Data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fm, err := NewFileMigrator(dir, newTestConnection(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	up := fm.Migrations["up"]
+	if len(up) != 3 {
+		t.Fatalf("expected 3 up migrations, got %d", len(up))
+	}
+	for _, m := range up {
+		if filepath.Dir(m.Path) != dir {
+			t.Errorf("expected migration path in %s, got %s", dir, m.Path)
+		}
+	}
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	// missing directory has no migrations
+	fm, err = NewFileMigrator(filepath.Join(dir, "missing"), newTestConnection(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fm.Migrations["up"]) != 0 {
+		t.Fatalf("expected no migrations, got %d", len(fm.Migrations["up"]))
+	}
+}
+
+func TestMigration_SQLTemplate(t *testing.T) {
+	t.Setenv("FLOW_TEST_CURRENCY", "USD")
+	c := newTestConnection(t)
+
+	content := `-- {{ .Dialect }} {{ .Database }}
+INSERT INTO {{ quoteIdent "order" }} (currency, fallback) VALUES ('{{ env "FLOW_TEST_CURRENCY" "EUR" }}', '{{ env "FLOW_TEST_MISSING" "EUR" }}');
+INSERT INTO settings (name) VALUES ('{{ .Settings.app_name }}');`
+	m := Migration{
+		Path: "20200101000001_settings.up.sql",
+		Type: "sql",
+		Content: func(Migration) ([]byte, error) {
+			return []byte(content), nil
+		},
+		settings: map[string]string{"app_name": "flow"},
+	}
+
+	sql, err := m.SQL(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `-- sqlite3 ` + c.Details.Database + `
+INSERT INTO "order" (currency, fallback) VALUES ('USD', 'EUR');
+INSERT INTO settings (name) VALUES ('flow');`
+	if sql != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, sql)
+	}
+
+	m.Type = "fizz"
+	content = `{{ if eq .Dialect "sqlite3" }}drop_table("{{ .Settings.app_name }}"){{ end }}`
+	if sql, err = m.SQL(c); err != nil || sql != "DROP TABLE flow;" {
+		t.Fatalf("expected rendered fizz migration, got %s, %v", sql, err)
+	}
+}
+
+func TestMigrator_Settings(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20200101000001_settings.up.sql":   {Data: []byte("CREATE TABLE {{ .Settings.table }} (id INTEGER);")},
+		"20200101000001_settings.down.sql": {Data: []byte("DROP TABLE {{ .Settings.table }};")},
+	}
+	c := newTestConnection(t)
+	fm, err := NewFSMigrator(fsys, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm.Settings = map[string]string{"table": "configured"}
+
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Store.Exec("SELECT * FROM configured"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Down(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Store.Exec("SELECT * FROM configured"); err == nil {
+		t.Fatal("expected configured table to be dropped")
+	}
+}
@@ -0,0 +1,434 @@
+package dbe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/flow/dbe/dialect"
+)
+
+// fizzCall is a single statement of fizz migration, e.g.
+//
+//	add_column("users", "age", "int", {"null": true})
+type fizzCall struct {
+	Name  string
+	Args  []interface{}
+	Block []fizzCall
+	Pos   scanner.Position
+}
+
+// fizzToSQL translates fizz migration into SQL statements of given dialect.
+// Fizz is a dialect-neutral migration language:
+//
+//	create_table("users") {
+//		t.Column("email", "string", {"size": 100})
+//		t.Column("age", "int", {"null": true})
+//		t.Timestamps()
+//	}
+//	add_column("users", "active", "bool", {"default": true})
+//	add_index("users", "email", {"unique": true})
+//	rename_column("users", "email", "email_address")
+//	drop_index("users", "users_email_idx")
+//	drop_column("users", "active")
+//	drop_table("users")
+//	sql("UPDATE users SET age = 0")
+//
+// Tables created without primary key column get `id` int primary key.
+// Column options are null, primary, size, default and default_raw (SQL expression).
+func fizzToSQL(content string, d dialect.Dialect) (string, error) {
+	calls, err := parseFizz(content)
+	if err != nil {
+		return "", err
+	}
+
+	stmts := []string{}
+	for _, call := range calls {
+		stmt, err := call.sql(d)
+		if err != nil {
+			return "", errors.Wrapf(err, "%s %s", call.Pos, call.Name)
+		}
+		stmts = append(stmts, stmt+";")
+	}
+	return strings.Join(stmts, "\n"), nil
+}
+
+// sql translates call into SQL statement of given dialect
+func (c fizzCall) sql(d dialect.Dialect) (string, error) {
+	switch c.Name {
+	case "create_table":
+		return c.createTable(d)
+	case "drop_table":
+		table, err := c.stringArg(0)
+		if err != nil {
+			return "", err
+		}
+		return d.DropTableStmt(table)
+	case "add_column":
+		table, err := c.stringArg(0)
+		if err != nil {
+			return "", err
+		}
+		column, err := c.column(1)
+		if err != nil {
+			return "", err
+		}
+		return d.AddColumnStmt(table, column)
+	case "drop_column":
+		table, err := c.stringArg(0)
+		if err != nil {
+			return "", err
+		}
+		column, err := c.stringArg(1)
+		if err != nil {
+			return "", err
+		}
+		return d.DropColumnStmt(table, column)
+	case "rename_column":
+		table, err := c.stringArg(0)
+		if err != nil {
+			return "", err
+		}
+		oldName, err := c.stringArg(1)
+		if err != nil {
+			return "", err
+		}
+		newName, err := c.stringArg(2)
+		if err != nil {
+			return "", err
+		}
+		return d.RenameColumnStmt(table, oldName, newName)
+	case "add_index":
+		return c.addIndex(d)
+	case "drop_index":
+		table, err := c.stringArg(0)
+		if err != nil {
+			return "", err
+		}
+		name, err := c.stringArg(1)
+		if err != nil {
+			return "", err
+		}
+		return d.DropIndexStmt(table, name)
+	case "sql":
+		stmt, err := c.stringArg(0)
+		return strings.TrimSuffix(strings.TrimSpace(stmt), ";"), err
+	}
+	return "", errors.New("unknown statement")
+}
+
+// createTable translates create_table call and its column definitions
+func (c fizzCall) createTable(d dialect.Dialect) (string, error) {
+	table, err := c.stringArg(0)
+	if err != nil {
+		return "", err
+	}
+
+	columns := []dialect.Column{}
+	hasPrimary := false
+	for _, call := range c.Block {
+		switch call.Name {
+		case "t.Column":
+			column, err := call.column(0)
+			if err != nil {
+				return "", errors.Wrapf(err, "%s %s", call.Pos, call.Name)
+			}
+			hasPrimary = hasPrimary || column.Primary
+			columns = append(columns, column)
+		case "t.Timestamps":
+			columns = append(columns,
+				dialect.Column{Name: "created_at", Type: "timestamp"},
+				dialect.Column{Name: "updated_at", Type: "timestamp"},
+			)
+		default:
+			return "", errors.Errorf("%s unknown table statement %s", call.Pos, call.Name)
+		}
+	}
+
+	if !hasPrimary {
+		columns = append([]dialect.Column{{Name: "id", Type: "int", Primary: true}}, columns...)
+	}
+	return d.CreateTableStmt(table, columns)
+}
+
+// addIndex translates add_index call, index columns are given as
+// a single column name or list of column names
+func (c fizzCall) addIndex(d dialect.Dialect) (string, error) {
+	table, err := c.stringArg(0)
+	if err != nil {
+		return "", err
+	}
+	if len(c.Args) < 2 {
+		return "", errors.New("index columns are missing")
+	}
+
+	index := dialect.Index{}
+	switch v := c.Args[1].(type) {
+	case string:
+		index.Columns = []string{v}
+	case []interface{}:
+		for _, col := range v {
+			s, ok := col.(string)
+			if !ok {
+				return "", errors.Errorf("index column must be a string, got %v", col)
+			}
+			index.Columns = append(index.Columns, s)
+		}
+	default:
+		return "", errors.Errorf("index columns must be a string or list of strings, got %v", v)
+	}
+
+	opts, err := c.options(2)
+	if err != nil {
+		return "", err
+	}
+	index.Unique, _ = opts["unique"].(bool)
+	index.Name, _ = opts["name"].(string)
+	if index.Name == "" {
+		index.Name = fmt.Sprintf("%s_%s_idx", table, strings.Join(index.Columns, "_"))
+	}
+	return d.AddIndexStmt(table, index)
+}
+
+// column reads column name, type and options starting at argument i
+func (c fizzCall) column(i int) (dialect.Column, error) {
+	column := dialect.Column{}
+
+	name, err := c.stringArg(i)
+	if err != nil {
+		return column, err
+	}
+	typ, err := c.stringArg(i + 1)
+	if err != nil {
+		return column, err
+	}
+	opts, err := c.options(i + 2)
+	if err != nil {
+		return column, err
+	}
+
+	column.Name = name
+	column.Type = typ
+	column.Null, _ = opts["null"].(bool)
+	column.Primary, _ = opts["primary"].(bool)
+	if size, ok := opts["size"].(int); ok {
+		column.Size = size
+	}
+
+	if v, ok := opts["default"]; ok {
+		switch v := v.(type) {
+		case string:
+			column.Default = "'" + strings.Replace(v, "'", "''", -1) + "'"
+		case bool:
+			column.Default = strings.ToUpper(strconv.FormatBool(v))
+		default:
+			column.Default = fmt.Sprint(v)
+		}
+	}
+	if v, ok := opts["default_raw"].(string); ok {
+		column.Default = v
+	}
+	return column, nil
+}
+
+// stringArg returns string argument at index i
+func (c fizzCall) stringArg(i int) (string, error) {
+	if i >= len(c.Args) {
+		return "", errors.Errorf("argument %d is missing", i+1)
+	}
+	s, ok := c.Args[i].(string)
+	if !ok {
+		return "", errors.Errorf("argument %d must be a string, got %v", i+1, c.Args[i])
+	}
+	return s, nil
+}
+
+// options returns options argument at index i, it is optional
+func (c fizzCall) options(i int) (map[string]interface{}, error) {
+	if i >= len(c.Args) {
+		return map[string]interface{}{}, nil
+	}
+	opts, ok := c.Args[i].(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("argument %d must be options map, got %v", i+1, c.Args[i])
+	}
+	return opts, nil
+}
+
+// fizzParser parses fizz migration into list of calls
+type fizzParser struct {
+	s   scanner.Scanner
+	tok rune
+}
+
+// parseFizz parses fizz migration content
+func parseFizz(content string) ([]fizzCall, error) {
+	p := &fizzParser{}
+	p.s.Init(strings.NewReader(content))
+	p.s.Filename = "fizz"
+	p.s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats | scanner.ScanStrings | scanner.ScanRawStrings | scanner.ScanComments | scanner.SkipComments
+	p.s.Error = func(*scanner.Scanner, string) {}
+	p.next()
+
+	calls := []fizzCall{}
+	for p.tok != scanner.EOF {
+		call, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+func (p *fizzParser) next() {
+	p.tok = p.s.Scan()
+}
+
+func (p *fizzParser) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("%s: %s", p.s.Position, fmt.Sprintf(format, args...))
+}
+
+func (p *fizzParser) expect(tok rune) error {
+	if p.tok != tok {
+		return p.errorf("expected %s, got %s", scanner.TokenString(tok), p.s.TokenText())
+	}
+	p.next()
+	return nil
+}
+
+// call parses `name(args...) { calls... }` where block is optional
+func (p *fizzParser) call() (fizzCall, error) {
+	call := fizzCall{Pos: p.s.Position}
+	if p.tok != scanner.Ident {
+		return call, p.errorf("expected statement, got %s", p.s.TokenText())
+	}
+	call.Name = p.s.TokenText()
+	p.next()
+	for p.tok == '.' {
+		p.next()
+		if p.tok != scanner.Ident {
+			return call, p.errorf("expected name after '.', got %s", p.s.TokenText())
+		}
+		call.Name += "." + p.s.TokenText()
+		p.next()
+	}
+
+	if err := p.expect('('); err != nil {
+		return call, err
+	}
+	for p.tok != ')' {
+		v, err := p.value()
+		if err != nil {
+			return call, err
+		}
+		call.Args = append(call.Args, v)
+		if p.tok != ',' {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(')'); err != nil {
+		return call, err
+	}
+
+	if p.tok == '{' {
+		p.next()
+		for p.tok != '}' {
+			if p.tok == scanner.EOF {
+				return call, p.errorf("unterminated block of %s", call.Name)
+			}
+			inner, err := p.call()
+			if err != nil {
+				return call, err
+			}
+			call.Block = append(call.Block, inner)
+		}
+		p.next()
+	}
+	return call, nil
+}
+
+// value parses string, number, bool, list or options map
+func (p *fizzParser) value() (interface{}, error) {
+	text := p.s.TokenText()
+	switch p.tok {
+	case scanner.String, scanner.RawString:
+		p.next()
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, p.errorf("invalid string %s", text)
+		}
+		return s, nil
+	case scanner.Int:
+		p.next()
+		return strconv.Atoi(text)
+	case scanner.Float:
+		p.next()
+		return strconv.ParseFloat(text, 64)
+	case '-':
+		p.next()
+		v, err := p.value()
+		switch v := v.(type) {
+		case int:
+			return -v, err
+		case float64:
+			return -v, err
+		}
+		return nil, p.errorf("expected number after '-'")
+	case scanner.Ident:
+		p.next()
+		switch text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, p.errorf("unexpected %s", text)
+	case '[':
+		p.next()
+		list := []interface{}{}
+		for p.tok != ']' {
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			if p.tok != ',' {
+				break
+			}
+			p.next()
+		}
+		return list, p.expect(']')
+	case '{':
+		p.next()
+		opts := map[string]interface{}{}
+		for p.tok != '}' {
+			key := p.s.TokenText()
+			switch p.tok {
+			case scanner.String:
+				key, _ = strconv.Unquote(key)
+			case scanner.Ident:
+			default:
+				return nil, p.errorf("expected option name, got %s", key)
+			}
+			p.next()
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			opts[key] = v
+			if p.tok != ',' {
+				break
+			}
+			p.next()
+		}
+		return opts, p.expect('}')
+	}
+	return nil, p.errorf("unexpected %s", text)
+}
@@ -0,0 +1,110 @@
+package dbe
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sedind/flow/dbe/dialect"
+)
+
+func TestFizzToSQL(t *testing.T) {
+	d, err := dialect.New("sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := `// users table
+create_table("users") {
+	t.Column("email", "string", {"size": 100})
+	t.Column("age", "int", {"null": true, "default": -1})
+	t.Column("name", "string", {default: "o'k"})
+	t.Timestamps()
+}
+add_column("users", "active", "bool", {"default": true})
+add_index("users", ["email", "age"], {"unique": true})
+rename_column("users", "name", "full_name")
+sql(` + "`UPDATE users SET age = 1;`" + `)
+drop_index("users", "users_email_age_idx")
+drop_column("users", "active")
+drop_table("users")
+`
+	expected := []string{
+		`CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email VARCHAR(100) NOT NULL,
+	age INTEGER NULL DEFAULT -1,
+	name VARCHAR(255) NOT NULL DEFAULT 'o''k',
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);`,
+		"ALTER TABLE users ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;",
+		"CREATE UNIQUE INDEX users_email_age_idx ON users (email, age);",
+		"ALTER TABLE users RENAME COLUMN name TO full_name;",
+		"UPDATE users SET age = 1;",
+		"DROP INDEX users_email_age_idx;",
+		"ALTER TABLE users DROP COLUMN active;",
+		"DROP TABLE users;",
+	}
+
+	out, err := fizzToSQL(src, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != strings.Join(expected, "\n") {
+		t.Fatalf("unexpected SQL:\n%s", out)
+	}
+}
+
+func TestFizzToSQL_Errors(t *testing.T) {
+	d, err := dialect.New("sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		src string
+		err string
+	}{
+		{`create_table("users") { t.Foo() }`, "unknown table statement t.Foo"},
+		{`add_column("users", 1)`, "argument 2 must be a string"},
+		{`add_column("users" "name")`, `expected ")"`},
+		{`create_table("users") {`, "unterminated block"},
+		{`rename_table("users", "people")`, "unknown statement"},
+	}
+	for _, tt := range tests {
+		_, err := fizzToSQL(tt.src, d)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.src, tt.err, err)
+		}
+	}
+}
+
+func TestFizzMigration(t *testing.T) {
+	fsys := testMigrationFiles()
+	delete(fsys, "20200101000003_t3.down.sql")
+	fsys["20200101000003_t3.down.fizz"] = &fstest.MapFile{Data: []byte(`drop_table("t3")`)}
+	fsys["20200101000004_t4.up.fizz"] = &fstest.MapFile{Data: []byte(`create_table("t4") { t.Column("name", "string", {"null": true}) }`)}
+	fsys["20200101000004_t4.down.fizz"] = &fstest.MapFile{Data: []byte(`drop_table("t4")`)}
+
+	c := newTestConnection(t)
+	fm, err := NewFSMigrator(fsys, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Store.Exec("INSERT INTO t4 (name) VALUES (NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fm.Down(2); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"t3", "t4"} {
+		if _, err := c.Store.Exec("SELECT * FROM " + table); err == nil {
+			t.Fatalf("expected %s table to be dropped", table)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package dbe
+
+import (
+	"context"
+	"sync"
+)
+
+// Hook is called around every statement executed on connection it is
+// registered with, including statements executed by queries, transactions
+// and migrators. It may be used for metrics, tracing or audit logging.
+//
+//	type metricsHook struct{}
+//
+//	func (metricsHook) BeforeQuery(ctx context.Context, e *dbe.QueryEvent) context.Context {
+//		return ctx
+//	}
+//
+//	func (metricsHook) AfterQuery(ctx context.Context, e *dbe.QueryEvent) {
+//		queryDuration.Observe(e.Duration.Seconds())
+//	}
+//
+//	conn.AddHook(metricsHook{})
+type Hook interface {
+	// BeforeQuery is called before statement is executed. Returned context
+	// is used to execute the statement and is passed to AfterQuery.
+	BeforeQuery(ctx context.Context, e *QueryEvent) context.Context
+	// AfterQuery is called after statement is executed
+	AfterQuery(ctx context.Context, e *QueryEvent)
+}
+
+// hookSet holds hooks of a connection, shared by its copies
+type hookSet struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+// list returns registered hooks
+func (hs *hookSet) list() []Hook {
+	if hs == nil {
+		return nil
+	}
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return hs.hooks
+}
+
+// AddHook registers hook called around every statement executed on the
+// connection, its transactions and copies. Hooks are called in order
+// they are added.
+func (c *Connection) AddHook(h Hook) {
+	c.hooks.mu.Lock()
+	defer c.hooks.mu.Unlock()
+	c.hooks.hooks = append(c.hooks.hooks, h)
+}
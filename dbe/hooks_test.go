@@ -0,0 +1,71 @@
+package dbe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type hookKey struct{}
+
+// recordingHook records events of executed statements
+type recordingHook struct {
+	before []string
+	after  []*QueryEvent
+}
+
+func (h *recordingHook) BeforeQuery(ctx context.Context, e *QueryEvent) context.Context {
+	h.before = append(h.before, e.Statement)
+	return context.WithValue(ctx, hookKey{}, e.Statement)
+}
+
+func (h *recordingHook) AfterQuery(ctx context.Context, e *QueryEvent) {
+	if ctx.Value(hookKey{}) != e.Statement {
+		panic("context returned by BeforeQuery was not passed to AfterQuery")
+	}
+	h.after = append(h.after, e)
+}
+
+func TestConnection_AddHook(t *testing.T) {
+	c := newTestConnection(t, "ALTER TABLE users ADD COLUMN password TEXT")
+	h := &recordingHook{}
+	c.AddHook(h)
+
+	err := c.Transaction(func(tx *Connection) error {
+		return tx.Create(&testAccountUser{Name: "a", Password: "secret"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.WithContext(context.Background()).Query().Where("name = ?", "missing").First(&testUser{})
+	if err == nil {
+		t.Fatal("expected error of missing user")
+	}
+
+	if len(h.before) != len(h.after) || len(h.after) == 0 {
+		t.Fatalf("expected every statement to be reported before and after, got %d and %d", len(h.before), len(h.after))
+	}
+
+	var insert, sel *QueryEvent
+	for _, e := range h.after {
+		switch {
+		case strings.HasPrefix(e.Statement, "INSERT"):
+			insert = e
+		case strings.HasPrefix(e.Statement, "SELECT"):
+			sel = e
+		}
+	}
+	if insert == nil || insert.TxID == 0 || insert.Rows != 1 || insert.Err != nil {
+		t.Fatalf("expected insert executed in transaction, got %+v", insert)
+	}
+	if args := fmt.Sprint(insert.MaskedArgs()); strings.Contains(args, "secret") {
+		t.Fatalf("expected masked password, got %s", args)
+	}
+	if fmt.Sprint(insert.Args) == fmt.Sprint(insert.MaskedArgs()) {
+		t.Fatalf("expected hooks to receive unmasked arguments, got %v", insert.Args)
+	}
+	if sel == nil || sel.TxID != 0 || sel.Err != nil || sel.Duration <= 0 {
+		t.Fatalf("expected select executed outside of transaction, got %+v", sel)
+	}
+}
@@ -1,6 +1,9 @@
 package dbe
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -16,24 +19,59 @@ type Migration struct {
 	Name string
 	// Direction of the migration (up)
 	Direction string
+	// Type of the migration file (sql or fizz)
+	Type string
 	// Runner function to run/execute the migration
 	Runner func(Migration, *Connection) error
+	// Content function to read raw content of the migration,
+	// nil for migrations which are not defined by SQL file
+	Content func(Migration) ([]byte, error)
+	// settings are application settings passed to migration template
+	settings map[string]string
 }
 
 // Run the migration. Returns an error if there is
 // no mf.Runner defined.
 func (m Migration) Run(conn *Connection) error {
 	if m.Runner == nil {
-		return errors.Errorf("no runner defined for $s", m.Path)
+		return errors.Errorf("no runner defined for %s", m.Path)
 	}
 
 	return m.Runner(m, conn)
 }
 
+// Checksum returns SHA-256 checksum of the migration content,
+// empty for migrations without content
+func (m Migration) Checksum() (string, error) {
+	if m.Content == nil {
+		return "", nil
+	}
+	raw, err := m.Content(m)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SQL returns the migration content rendered as it is executed on conn,
+// empty for migrations without content
+func (m Migration) SQL(conn *Connection) (string, error) {
+	if m.Content == nil {
+		return "", nil
+	}
+	raw, err := m.Content(m)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return migrateContent(m, conn, bytes.NewReader(raw))
+}
+
 // Exists checks if migration exists in DB
 func (m Migration) Exists(conn *Connection, migrationTable string) (bool, error) {
 	var count int
-	err := conn.Store.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE version = ?", migrationTable), m.Version).Scan(&count)
+	query := conn.Dialect.TranslateSQL(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE version = ?", migrationTable))
+	err := conn.Store.QueryRowContext(conn.Context(), query, m.Version).Scan(&count)
 
 	if err != nil {
 		return false, errors.WithStack(err)
@@ -0,0 +1,75 @@
+package dbe
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// registry of Go code migrations
+var (
+	migrationsMu         sync.Mutex
+	registeredMigrations = map[string]Migrations{}
+)
+
+// RegisterMigration registers migration written in Go code. Registered
+// migrations are run by every `Migrator` together with its own migrations,
+// ordered by version. up and down functions are executed within transaction,
+// nil down function makes rolling back the migration a no-op.
+// It panics if migration with the same version is already registered.
+//
+//	func init() {
+//		dbe.RegisterMigration("20180601120000", "backfill_slugs", backfillSlugs, nil)
+//	}
+func RegisterMigration(version string, name string, up func(*Connection) error, down func(*Connection) error) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	for _, mg := range registeredMigrations["up"] {
+		if mg.Version == version {
+			panic(fmt.Sprintf("dbe: migration version %s registered twice", version))
+		}
+	}
+
+	path := ""
+	if _, file, line, ok := runtime.Caller(1); ok {
+		path = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	for dir, fn := range map[string]func(*Connection) error{"up": up, "down": down} {
+		registeredMigrations[dir] = append(registeredMigrations[dir], Migration{
+			Path:      path,
+			Version:   version,
+			Name:      name,
+			Direction: dir,
+			Runner:    funcRunner(fn),
+		})
+	}
+}
+
+// funcRunner creates migration runner executing fn within transaction
+func funcRunner(fn func(*Connection) error) func(Migration, *Connection) error {
+	return func(migration Migration, conn *Connection) error {
+		if fn == nil {
+			return nil
+		}
+
+		err := conn.Transaction(func(tx *Connection) error {
+			return errors.Wrapf(fn(tx), "error executing %s", migration.Path)
+		})
+		if err != nil {
+			fmt.Printf("Migration %s Failed. Rolling back...", migration.Name)
+		}
+		return err
+	}
+}
+
+// registeredMigrationsFor returns copy of registered migrations for given direction
+func registeredMigrationsFor(dir string) Migrations {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	return append(Migrations{}, registeredMigrations[dir]...)
+}
@@ -0,0 +1,92 @@
+package dbe
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// appliedVersions returns sorted versions of applied migrations
+func appliedVersions(t *testing.T, m Migrator) []string {
+	t.Helper()
+
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := []string{}
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// restoreRegisteredMigrations removes migrations registered by the test
+func restoreRegisteredMigrations(t *testing.T) {
+	up, down := registeredMigrationsFor("up"), registeredMigrationsFor("down")
+	t.Cleanup(func() {
+		migrationsMu.Lock()
+		defer migrationsMu.Unlock()
+		registeredMigrations = map[string]Migrations{"up": up, "down": down}
+	})
+}
+
+func TestRegisterMigration(t *testing.T) {
+	restoreRegisteredMigrations(t)
+	c := newTestConnection(t)
+
+	RegisterMigration("20200101000001", "create_t1", func(tx *Connection) error {
+		_, err := tx.Store.Exec("CREATE TABLE t1 (id INTEGER)")
+		return err
+	}, func(tx *Connection) error {
+		_, err := tx.Store.Exec("DROP TABLE t1")
+		return err
+	})
+	RegisterMigration("20200101000002", "fill_t1", func(tx *Connection) error {
+		if _, err := tx.Store.Exec("INSERT INTO t1 VALUES (1)"); err != nil {
+			return err
+		}
+		return errTest
+	}, nil)
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected panic of version registered twice")
+			}
+		}()
+		RegisterMigration("20200101000001", "again", nil, nil)
+	}()
+
+	m := NewMigrator(c)
+	err := m.Up()
+	if err == nil || !strings.Contains(err.Error(), errTest.Error()) {
+		t.Fatalf("expected error of failed migration, got %v", err)
+	}
+	if v := appliedVersions(t, m); fmt.Sprint(v) != "[20200101000001]" {
+		t.Fatalf("expected only first migration to be applied, got %v", v)
+	}
+	var n int
+	if err := c.Store.QueryRow("SELECT count(*) FROM t1").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expected failed migration to be rolled back, got %d rows", n)
+	}
+
+	if err := m.Down(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Store.Exec("SELECT * FROM t1"); err == nil {
+		t.Fatal("expected t1 table to be dropped")
+	}
+}
+
+func TestMigration_RunWithoutRunner(t *testing.T) {
+	err := Migration{Path: "20200101000001_t1.up.sql"}.Run(nil)
+	if err == nil || err.Error() != "no runner defined for 20200101000001_t1.up.sql" {
+		t.Fatalf("expected missing runner error, got %v", err)
+	}
+}
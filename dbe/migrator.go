@@ -1,22 +1,25 @@
 package dbe
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
 	"regexp"
 	"sort"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
-var migrationRegEx = regexp.MustCompile(`(\d+)_([^\.]+)(\.[a-z]+)?\.(up|down)\.(sql)`)
+var migrationRegEx = regexp.MustCompile(`(\d+)_([^\.]+)(\.[a-z]+)?\.(up|down)\.(sql|fizz)`)
 
 // default migartion schema name
 const defaultMigrationSchema string = "schema_migration"
 
-// NewMigrator returns a new "blank" migrator.
+// NewMigrator returns a new "blank" migrator holding only migrations
+// registered with `RegisterMigration`.
 // A "blank" Migrator should only be used as
 //the basis for a new type of migration system.
 // It is recommended to use something like FileMigrator.
@@ -24,8 +27,8 @@ func NewMigrator(conn *Connection) Migrator {
 	return Migrator{
 		Conn: conn,
 		Migrations: map[string]Migrations{
-			"up":   Migrations{},
-			"down": Migrations{},
+			"up":   registeredMigrationsFor("up"),
+			"down": registeredMigrationsFor("down"),
 		},
 	}
 }
@@ -38,53 +41,165 @@ type Migrator struct {
 	Conn       *Connection
 	SchemaPath string
 	Migrations map[string]Migrations
+	// DryRun prints SQL of migrations which would be run
+	// without executing them
+	DryRun bool
+	// Settings are application settings available
+	// to migration templates as .Settings
+	Settings map[string]string
 }
 
 // Up runs pending "up" migrations and applies them to the database.
 func (m Migrator) Up() error {
 	return m.exec(func() error {
+		return m.up(func(version string) bool { return true })
+	})
+}
 
-		migrations := m.Migrations["up"]
-		sort.Sort(migrations)
-		for _, migration := range migrations {
-			exists, err := migration.Exists(m.Conn, m.migrationSchema())
-			if err != nil {
-				return errors.Wrapf(err, "problem checking for migration version %s", migration.Version)
-			}
+// UpTo runs pending "up" migrations up to and including the given version.
+func (m Migrator) UpTo(version string) error {
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
+	return m.exec(func() error {
+		return m.up(func(v string) bool { return v <= version })
+	})
+}
 
-			if exists {
-				continue //migration is executed skip to next
-			}
+// up applies pending migrations whose version matches filter
+func (m Migrator) up(filter func(version string) bool) error {
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return errors.Wrap(err, "problem checking for applied migrations")
+	}
 
-			err = migration.Run(m.Conn)
-			if err != nil {
-				return errors.WithStack(err)
-			}
+	migrations := m.Migrations["up"]
+	sort.Sort(migrations)
+	for _, migration := range migrations {
+		if !filter(migration.Version) {
+			continue
+		}
+		migration.settings = m.Settings
+		if _, ok := applied[migration.Version]; ok {
+			continue //migration is executed skip to next
+		}
 
-			_, err = m.Conn.Store.Exec(fmt.Sprintf("insert into %s (version,name) values (?,?)", m.migrationSchema()), migration.Version, migration.Name)
-			if err != nil {
-				return errors.WithStack(err)
+		if m.DryRun {
+			if err := m.printMigrationSQL(migration); err != nil {
+				return err
 			}
+			continue
+		}
 
-			fmt.Printf("> %s\n", migration.Name)
+		checksum, err := migration.Checksum()
+		if err != nil {
+			return errors.Wrapf(err, "problem computing checksum of migration version %s", migration.Version)
 		}
-		return nil
-	})
+
+		err = migration.Run(m.Conn)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		query := m.Conn.Dialect.TranslateSQL(fmt.Sprintf("insert into %s (version,name,checksum) values (?,?,?)", m.migrationSchema()))
+		_, err = m.Conn.Store.ExecContext(m.Conn.Context(), query, migration.Version, migration.Name, sql.NullString{String: checksum, Valid: checksum != ""})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		fmt.Printf("> %s\n", migration.Name)
+	}
+	return nil
 }
 
 // Down runs pending "down" migrations and rolls back the
 // database by the specified number of steps.
 func (m Migrator) Down(step int) error {
 	return m.exec(func() error {
-		count, err := m.getExecutedMigrationsCount()
+		return m.down(step, "")
+	})
+}
+
+// DownTo runs "down" migrations of applied migrations newer than
+// the given version, so the given version is the last applied one.
+func (m Migrator) DownTo(version string) error {
+	if err := m.checkVersion(version); err != nil {
+		return err
+	}
+	return m.exec(func() error {
+		return m.down(-1, version)
+	})
+}
+
+// Redo rolls back the last applied migration and applies it again.
+func (m Migrator) Redo() error {
+	return m.exec(func() error {
+		applied, err := m.appliedMigrations()
 		if err != nil {
-			return errors.Wrap(err, "migration down: unable to count migrations")
+			return errors.Wrap(err, "problem checking for applied migrations")
+		}
+
+		last := ""
+		for version := range applied {
+			if version > last {
+				last = version
+			}
+		}
+		if last == "" {
+			return nil // nothing to redo
+		}
+
+		// roll back exactly the last applied version, down migrations
+		// are not necessarily ordered the same way as applied ones
+		found := false
+		for _, migration := range m.Migrations["down"] {
+			if migration.Version == last {
+				found = true
+				if err := m.rollback(migration); err != nil {
+					return err
+				}
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("down migration of version %s not found", last)
+		}
+
+		if m.DryRun {
+			for _, migration := range m.Migrations["up"] {
+				if migration.Version == last {
+					migration.settings = m.Settings
+					return m.printMigrationSQL(migration)
+				}
+			}
+			return nil
 		}
+		return m.up(func(version string) bool { return version == last })
+	})
+}
+
+// down rolls back step number of applied migrations or,
+// when to is provided, all applied migrations newer than to
+func (m Migrator) down(step int, to string) error {
+	applied, err := m.appliedMigrations()
+	if err != nil {
+		return errors.Wrap(err, "migration down: unable to count migrations")
+	}
+	count := len(applied)
 
-		migrations := m.Migrations["down"]
-		// sorting magic :)
-		sort.Sort(sort.Reverse(migrations))
+	migrations := m.Migrations["down"]
+	// sorting magic :)
+	sort.Sort(sort.Reverse(migrations))
 
+	if to != "" {
+		newer := Migrations{}
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok && migration.Version > to {
+				newer = append(newer, migration)
+			}
+		}
+		migrations = newer
+	} else {
 		//skip all executed migrations
 		if len(migrations) > count {
 			migrations = migrations[len(migrations)-count:]
@@ -94,41 +209,75 @@ func (m Migrator) Down(step int) error {
 		if step > 0 && len(migrations) >= step {
 			migrations = migrations[:step]
 		}
-		for _, migration := range migrations {
-			exists, err := migration.Exists(m.Conn, m.migrationSchema())
-			if err != nil || !exists {
-				return errors.Wrapf(err, "problem checking for migration version %s", migration.Version)
-			}
+	}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			return nil
+		}
+		if err := m.rollback(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			err = migration.Run(m.Conn)
-			if err != nil {
-				return errors.WithStack(err)
-			}
+// rollback runs given down migration and removes its version from migrations schema
+func (m Migrator) rollback(migration Migration) error {
+	migration.settings = m.Settings
 
-			_, err = m.Conn.Store.Exec(fmt.Sprintf("delete from %s where version = ? ", m.migrationSchema()), migration.Version)
-			if err != nil {
-				return errors.WithStack(err)
-			}
-			fmt.Printf("< %s\n", migration.Name)
+	if m.DryRun {
+		return m.printMigrationSQL(migration)
+	}
 
+	err := migration.Run(m.Conn)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	query := m.Conn.Dialect.TranslateSQL(fmt.Sprintf("delete from %s where version = ? ", m.migrationSchema()))
+	_, err = m.Conn.Store.ExecContext(m.Conn.Context(), query, migration.Version)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Printf("< %s\n", migration.Name)
+	return nil
+}
+
+// checkVersion verifies there is a migration with the given version
+func (m Migrator) checkVersion(version string) error {
+	for _, migration := range m.Migrations["up"] {
+		if migration.Version == version {
+			return nil
 		}
-		return nil
-	})
+	}
+	return errors.Errorf("migration version %s does not exist", version)
 }
 
 // Status prints out the status of applied/pending migrations.
+// Applied migrations whose content changed after being applied
+// are reported as changed.
 func (m Migrator) Status() error {
 	return m.exec(func() error {
+		applied, err := m.appliedMigrations()
+		if err != nil {
+			return errors.Wrap(err, "problem checking for applied migrations")
+		}
+
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.TabIndent)
 		fmt.Fprintln(w, "Version\t\tName\t\tStatus")
-		for _, migration := range m.Migrations["up"] {
-			exists, err := migration.Exists(m.Conn, m.migrationSchema())
-			if err != nil {
-				return errors.Wrapf(err, "problem checking for migration version %s", migration.Version)
-			}
+		migrations := m.Migrations["up"]
+		sort.Sort(migrations)
+		for _, migration := range migrations {
 			status := "Pending"
-			if exists {
+			if checksum, ok := applied[migration.Version]; ok {
 				status = "Applied"
+				current, err := migration.Checksum()
+				if err != nil {
+					return errors.Wrapf(err, "problem computing checksum of migration version %s", migration.Version)
+				}
+				if checksum != "" && current != checksum {
+					status = "Changed"
+				}
 			}
 			fmt.Fprintf(w, "%s\t\t%s\t\t%s\t\t\n", migration.Version, migration.Name, status)
 		}
@@ -149,7 +298,7 @@ func (m Migrator) Reset() error {
 func (m Migrator) CreateSchemaMigrations() error {
 	//check if migrations table exists
 	if m.hasMigrationsSchema() {
-		return nil // nothing to to here, go back
+		return m.addChecksumColumn()
 	}
 	dialect := m.Conn.Details.Dialect
 	sql := m.getMigrationsSchema(dialect)
@@ -158,7 +307,7 @@ func (m Migrator) CreateSchemaMigrations() error {
 		return errors.Errorf("Version Schema missing for dialect %s", dialect)
 	}
 
-	_, err := m.Conn.Store.Exec(sql)
+	_, err := m.Conn.Store.ExecContext(m.Conn.Context(), sql)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -166,26 +315,65 @@ func (m Migrator) CreateSchemaMigrations() error {
 	return nil
 }
 
-// exec internal helper execution function
-func (m Migrator) exec(fn func() error) error {
+// exec internal helper execution function,
+// fn is executed holding the migration lock
+func (m Migrator) exec(fn func() error) (err error) {
 	now := time.Now()
 	defer printTimer(now)
 
-	err := m.CreateSchemaMigrations()
+	if err := m.checkVersions(); err != nil {
+		return err
+	}
+
+	unlock, err := m.lock()
 	if err != nil {
-		return errors.Wrap(err, "Migrator: problem creating schema migrations")
+		return errors.Wrap(err, "Migrator: problem acquiring migration lock")
+	}
+	defer func() {
+		if uerr := unlock(); err == nil {
+			err = uerr
+		}
+	}()
+
+	if !m.DryRun {
+		err = m.CreateSchemaMigrations()
+		if err != nil {
+			return errors.Wrap(err, "Migrator: problem creating schema migrations")
+		}
 	}
 
 	return fn()
 }
 
+// checkVersions verifies there are no two migrations with the same version,
+// eg. SQL file and registered Go migration
+func (m Migrator) checkVersions() error {
+	for dir, migrations := range m.Migrations {
+		paths := map[string]string{}
+		for _, migration := range migrations {
+			if p, ok := paths[migration.Version]; ok {
+				return errors.Errorf("duplicate %s migration version %s: %s and %s", dir, migration.Version, p, migration.Path)
+			}
+			paths[migration.Version] = migration.Path
+		}
+	}
+	return nil
+}
+
 func (m Migrator) hasMigrationsSchema() bool {
 	var currentDatabase string
 	var count int
 
-	m.Conn.Store.QueryRow("SELECT DATABASE()").Scan(&currentDatabase)
+	switch m.Conn.Details.Dialect {
+	case "postgres":
+		m.Conn.Store.QueryRowContext(m.Conn.Context(), "SELECT count(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1", m.migrationSchema()).Scan(&count)
+	case "sqlite3":
+		m.Conn.Store.QueryRowContext(m.Conn.Context(), "SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", m.migrationSchema()).Scan(&count)
+	default:
+		m.Conn.Store.QueryRowContext(m.Conn.Context(), "SELECT DATABASE()").Scan(&currentDatabase)
 
-	m.Conn.Store.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_name = ?", currentDatabase, m.migrationSchema()).Scan(&count)
+		m.Conn.Store.QueryRowContext(m.Conn.Context(), "SELECT count(*) FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_name = ?", currentDatabase, m.migrationSchema()).Scan(&count)
+	}
 	return count > 0
 }
 
@@ -193,15 +381,73 @@ func (m Migrator) getMigrationsSchema(dialect string) string {
 	switch dialect {
 	case "mysql":
 		return fmt.Sprintf(mySQLMigrationTblTpl, m.migrationSchema())
+	case "postgres":
+		return fmt.Sprintf(postgresMigrationTblTpl, m.migrationSchema())
+	case "sqlite3":
+		return fmt.Sprintf(sqliteMigrationTblTpl, m.migrationSchema())
 	}
 	return ""
 }
 
-func (m Migrator) getExecutedMigrationsCount() (int, error) {
-	var count int
-	err := m.Conn.Store.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", m.migrationSchema())).Scan(&count)
+// appliedMigrations returns checksums of applied migrations by version,
+// checksum is empty for migrations applied without it
+func (m Migrator) appliedMigrations() (map[string]string, error) {
+	applied := map[string]string{}
+	if !m.hasMigrationsSchema() {
+		return applied, nil
+	}
+
+	checksum := "NULL"
+	if m.hasChecksumColumn() {
+		checksum = "checksum"
+	}
+
+	rows := []struct {
+		Version  string         `db:"version"`
+		Checksum sql.NullString `db:"checksum"`
+	}{}
+	err := m.Conn.Store.SelectContext(m.Conn.Context(), &rows, fmt.Sprintf("SELECT version, %s AS checksum FROM %s", checksum, m.migrationSchema()))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, r := range rows {
+		applied[r.Version] = r.Checksum.String
+	}
+	return applied, nil
+}
 
-	return count, err
+// hasChecksumColumn checks if migrations table has checksum column,
+// it is missing in tables created by older versions
+func (m Migrator) hasChecksumColumn() bool {
+	var checksum sql.NullString
+	err := m.Conn.Store.QueryRowContext(m.Conn.Context(), fmt.Sprintf("SELECT checksum FROM %s WHERE 1 = 0", m.migrationSchema())).Scan(&checksum)
+	return err == sql.ErrNoRows
+}
+
+// addChecksumColumn adds checksum column to migrations table created by older versions
+func (m Migrator) addChecksumColumn() error {
+	if m.hasChecksumColumn() {
+		return nil
+	}
+	_, err := m.Conn.Store.ExecContext(m.Conn.Context(), fmt.Sprintf("ALTER TABLE %s ADD COLUMN checksum VARCHAR(64) NULL", m.migrationSchema()))
+	return errors.WithStack(err)
+}
+
+// printMigrationSQL prints SQL executed by migration
+func (m Migrator) printMigrationSQL(migration Migration) error {
+	fmt.Printf("-- %s %s (%s)\n", migration.Version, migration.Name, migration.Direction)
+	if migration.Content == nil {
+		fmt.Printf("-- defined in Go code at %s, SQL is not available\n\n", migration.Path)
+		return nil
+	}
+
+	content, err := migration.SQL(m.Conn)
+	if err != nil {
+		return errors.Wrapf(err, "error processing %s", migration.Path)
+	}
+	fmt.Printf("%s\n\n", strings.TrimSpace(content))
+	return nil
 }
 
 func (m Migrator) migrationSchema() string {
@@ -226,5 +472,20 @@ var mySQLMigrationTblTpl = `
 	CREATE TABLE %s ( 
 	version NVARCHAR(14) NOT NULL, 
 	name NVARCHAR(255) NULL, 
+	checksum NVARCHAR(64) NULL, 
 	UNIQUE INDEX  schema_version_idx (version ASC));
 `
+
+var postgresMigrationTblTpl = `
+	CREATE TABLE %s (
+	version VARCHAR(14) NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NULL,
+	checksum VARCHAR(64) NULL);
+`
+
+var sqliteMigrationTblTpl = `
+	CREATE TABLE %s (
+	version TEXT NOT NULL PRIMARY KEY,
+	name TEXT NULL,
+	checksum TEXT NULL);
+`
@@ -0,0 +1,103 @@
+package dbe
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMigrationLocked is returned by migrator when migration lock
+// is held by another migrator longer than `MigrationLockTimeout`
+var ErrMigrationLocked = errors.New("migration lock is held by another migrator")
+
+// sessionStore executes statements within a single database session
+type sessionStore interface {
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+}
+
+// session returns store bound to a single database session, as database-level
+// locks are held by session. Returned function releases the session.
+func (c *Connection) session() (sessionStore, func(), error) {
+	s := c.Store
+	if is, ok := s.(*instrumentedStore); ok {
+		s = is.Store
+	}
+
+	d, ok := s.(*db)
+	if !ok || c.Details.Pool == 1 {
+		// transaction and pool of single connection are already bound
+		// to a single session, taking its connection would block other statements
+		return c.Store, func() {}, nil
+	}
+
+	conn, err := d.Connx(c.Context())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not get database session")
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// lock acquires database-level migration lock, so only one migrator
+// runs at a time. Returned function releases the lock.
+func (m Migrator) lock() (func() error, error) {
+	name := m.lockName()
+	timeout := m.Conn.Details.MigrationLockTimeout()
+
+	lockStmt, err := m.Conn.Dialect.LockStmt(name, timeout)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	unlockStmt, err := m.Conn.Dialect.UnlockStmt(name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	session, release, err := m.Conn.session()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := m.Conn.Context()
+	deadline := time.Now().Add(timeout)
+	for {
+		var locked int
+		if err := session.QueryRowContext(ctx, lockStmt).Scan(&locked); err != nil {
+			release()
+			return nil, errors.Wrapf(err, "could not acquire migration lock %s", name)
+		}
+		if locked == 1 {
+			break
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			release()
+			return nil, errors.Wrapf(ErrMigrationLocked, "could not acquire migration lock %s within %s", name, timeout)
+		}
+		if wait > time.Second {
+			wait = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, errors.Wrapf(ctx.Err(), "could not acquire migration lock %s", name)
+		case <-time.After(wait):
+		}
+	}
+
+	return func() error {
+		defer release()
+		_, err := session.ExecContext(ctx, unlockStmt)
+		return errors.Wrapf(err, "could not release migration lock %s", name)
+	}, nil
+}
+
+// lockName returns name of migration lock unique for database and migration schema
+func (m Migrator) lockName() string {
+	return fmt.Sprintf("%s.%s", m.Conn.Details.Database, m.migrationSchema())
+}
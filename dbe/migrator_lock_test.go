@@ -0,0 +1,40 @@
+package dbe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/flow/dbe/dialect"
+)
+
+// lockedDialect is SQLite dialect whose migration lock is never acquired
+type lockedDialect struct {
+	dialect.SQLite
+}
+
+func (lockedDialect) LockStmt(name string, timeout time.Duration) (string, error) {
+	return "SELECT 0", nil
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	fm, c := newTestMigrator(t)
+	c.Details.Options = map[string]string{"migration_lock_timeout": "10ms"}
+
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Dialect = lockedDialect{}
+	start := time.Now()
+	err := fm.Down(1)
+	if errors.Cause(err) != ErrMigrationLocked {
+		t.Fatalf("expected ErrMigrationLocked, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected lock timeout of 10ms, waited %s", elapsed)
+	}
+	if v := appliedVersions(t, fm.Migrator); len(v) != 3 {
+		t.Fatalf("expected no migration to be rolled back, got %v", v)
+	}
+}
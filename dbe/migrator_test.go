@@ -0,0 +1,154 @@
+package dbe
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// captureStdout returns output printed by fn
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+
+	err = fn()
+	os.Stdout = stdout
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return <-out
+}
+
+func TestMigrator_DryRun(t *testing.T) {
+	fm, c := newTestMigrator(t)
+	fm.DryRun = true
+
+	out := captureStdout(t, fm.Up)
+	for _, s := range []string{"-- 20200101000001 t1 (up)", "CREATE TABLE t1 (id INTEGER);", "CREATE TABLE t3 (id INTEGER);"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in dry run output:\n%s", s, out)
+		}
+	}
+	if v := appliedVersions(t, fm.Migrator); len(v) != 0 {
+		t.Fatalf("expected no applied migrations, got %v", v)
+	}
+	if _, err := c.Store.Exec("SELECT * FROM t1"); err == nil {
+		t.Fatal("expected t1 table not to be created")
+	}
+
+	fm.DryRun = false
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+	fm.DryRun = true
+	out = captureStdout(t, func() error { return fm.Down(1) })
+	if !strings.Contains(out, "DROP TABLE t3;") || strings.Contains(out, "t2") {
+		t.Fatalf("expected SQL of the last down migration, got:\n%s", out)
+	}
+	if v := appliedVersions(t, fm.Migrator); len(v) != 3 {
+		t.Fatalf("expected 3 applied migrations, got %v", v)
+	}
+}
+
+func TestMigrator_StatusChanged(t *testing.T) {
+	fsys := testMigrationFiles()
+	c := newTestConnection(t)
+	fm, err := NewFSMigrator(fsys, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.UpTo("20200101000002"); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys["20200101000001_t1.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE t1 (id INTEGER, name TEXT);")}
+	out := captureStdout(t, fm.Status)
+
+	status := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) == 3 {
+			status[fields[0]] = fields[2]
+		}
+	}
+	expected := map[string]string{"20200101000001": "Changed", "20200101000002": "Applied", "20200101000003": "Pending"}
+	for version, s := range expected {
+		if status[version] != s {
+			t.Errorf("expected %s status of %s, got %q", s, version, status[version])
+		}
+	}
+}
+
+func TestMigrator_UpToDownTo(t *testing.T) {
+	fm, _ := newTestMigrator(t)
+
+	if err := fm.UpTo("20200101000009"); err == nil {
+		t.Fatal("expected error of unknown version")
+	}
+	if err := fm.UpTo("20200101000002"); err != nil {
+		t.Fatal(err)
+	}
+	if v := appliedVersions(t, fm.Migrator); fmt.Sprint(v) != "[20200101000001 20200101000002]" {
+		t.Fatalf("expected first two migrations to be applied, got %v", v)
+	}
+
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.DownTo("20200101000001"); err != nil {
+		t.Fatal(err)
+	}
+	if v := appliedVersions(t, fm.Migrator); fmt.Sprint(v) != "[20200101000001]" {
+		t.Fatalf("expected only first migration to be applied, got %v", v)
+	}
+}
+
+func TestMigrator_Redo(t *testing.T) {
+	fm, c := newTestMigrator(t)
+
+	if err := fm.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Store.Exec("INSERT INTO t3 VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Redo(); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := c.Store.QueryRow("SELECT count(*) FROM t3").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expected t3 table to be recreated, got %d rows", n)
+	}
+	if v := appliedVersions(t, fm.Migrator); len(v) != 3 {
+		t.Fatalf("expected 3 applied migrations, got %v", v)
+	}
+
+	// the last version is redone when an older version is not applied
+	if _, err := c.Store.Exec("DROP TABLE t2; DELETE FROM schema_migration WHERE version = '20200101000002'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fm.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if v := appliedVersions(t, fm.Migrator); fmt.Sprint(v) != "[20200101000001 20200101000003]" {
+		t.Fatalf("expected last migration to be redone, got %v", v)
+	}
+}
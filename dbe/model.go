@@ -1,8 +1,10 @@
 package dbe
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -51,6 +53,12 @@ func (m *Model) setID(i interface{}) {
 		switch fbn.Kind() {
 		case reflect.Int, reflect.Int64:
 			fbn.SetInt(v.Int())
+		case reflect.String:
+			if b, ok := i.([]byte); ok {
+				fbn.SetString(string(b))
+			} else {
+				fbn.SetString(fmt.Sprint(i))
+			}
 		default:
 			fbn.Set(reflect.ValueOf(i))
 		}
@@ -96,6 +104,14 @@ func (m *Model) TouchCreatedAt() {
 	}
 }
 
+// asName returns alias used for model table in queries
+func (m *Model) asName() string {
+	if m.As != "" {
+		return m.As
+	}
+	return strings.Replace(m.TableName(), ".", "_", -1)
+}
+
 // TouchUpdatedAt sets current time to UpdatedAt field
 func (m *Model) TouchUpdatedAt() {
 	fbn, err := m.fieldByName("UpdatedAt")
@@ -186,3 +202,70 @@ func (m *Model) fieldByName(s string) (reflect.Value, error) {
 	}
 	return fVal, nil
 }
+
+// lockField returns integer `LockVersion` or `Version` field mapped to
+// a column and its column name, which are used for optimistic locking.
+// Empty column is returned if model does not use optimistic locking.
+func (m *Model) lockField() (reflect.Value, string) {
+	el := reflect.ValueOf(m.Value)
+	for el.Kind() == reflect.Ptr {
+		el = el.Elem()
+	}
+	if el.Kind() != reflect.Struct {
+		return reflect.Value{}, ""
+	}
+	for _, name := range []string{"LockVersion", "Version"} {
+		f, ok := el.Type().FieldByName(name)
+		if !ok {
+			continue
+		}
+		if tag := f.Tag.Get(modelTag); tag == "" || tag == "-" {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return el.FieldByIndex(f.Index), f.Tag.Get(modelTag)
+		}
+	}
+	return reflect.Value{}, ""
+}
+
+// lockColumn returns column of version field used for optimistic locking,
+// empty string if model does not use it
+func (m *Model) lockColumn() string {
+	_, column := m.lockField()
+	return column
+}
+
+// checkLockVersion returns ErrStaleObject if update of model using
+// optimistic locking did not affect any row
+func (m *Model) checkLockVersion(res sql.Result) error {
+	if m.lockColumn() == "" {
+		return nil
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if n == 0 {
+		return ErrStaleObject{Table: m.TableName(), ID: m.ID()}
+	}
+	return nil
+}
+
+// incrementLockVersion increments version of model using optimistic locking,
+// models which don't use it are not changed
+func (m *Model) incrementLockVersion() {
+	f, column := m.lockField()
+	if column == "" {
+		return
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.SetInt(f.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(f.Uint() + 1)
+	}
+}
@@ -0,0 +1,152 @@
+package dbe
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/inflect"
+)
+
+// association kinds supported as struct tags
+const (
+	hasManyTag    = "has_many"
+	belongsToTag  = "belongs_to"
+	manyToManyTag = "many_to_many"
+	fkIDTag       = "fk_id"
+	primaryIDTag  = "primary_id"
+)
+
+// association describes relation between model and other table
+// defined using struct tags
+//
+//	type User struct {
+//		ID     int     `db:"id"`
+//		Orders []Order `db:"-" has_many:"orders" fk_id:"user_id"`
+//	}
+//
+//	type Order struct {
+//		ID     int   `db:"id"`
+//		UserID int   `db:"user_id"`
+//		User   *User `db:"-" belongs_to:"users" fk_id:"user_id"`
+//		Tags   []Tag `db:"-" many_to_many:"order_tags" fk_id:"order_id" primary_id:"tag_id"`
+//	}
+type association struct {
+	// Kind of association (has_many, belongs_to, many_to_many)
+	Kind string
+	// Field holding associated value(s)
+	Field reflect.StructField
+	// Table holding associated rows, join table for many_to_many
+	Table string
+	// FkID is foreign key column
+	FkID string
+	// PrimaryID is column of join table referencing associated rows
+	PrimaryID string
+}
+
+// target returns struct type of associated model
+func (a association) target() reflect.Type {
+	t := a.Field.Type
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// associations gets model associations defined using struct tags
+func (m *Model) associations() (map[string]association, error) {
+	t := reflect.TypeOf(m.Value)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	assocs := map[string]association{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		for _, kind := range []string{hasManyTag, belongsToTag, manyToManyTag} {
+			table, ok := field.Tag.Lookup(kind)
+			if !ok {
+				continue
+			}
+
+			a := association{
+				Kind:      kind,
+				Field:     field,
+				Table:     table,
+				FkID:      field.Tag.Get(fkIDTag),
+				PrimaryID: field.Tag.Get(primaryIDTag),
+			}
+
+			ft := a.target()
+			if ft.Kind() != reflect.Struct {
+				return assocs, errors.Errorf("association %s.%s must be a struct or slice of structs", t.Name(), field.Name)
+			}
+			isSlice := field.Type.Kind() == reflect.Slice
+
+			switch kind {
+			case hasManyTag:
+				if !isSlice {
+					return assocs, errors.Errorf("has_many association %s.%s must be a slice", t.Name(), field.Name)
+				}
+				if a.FkID == "" {
+					a.FkID = inflect.Underscore(t.Name()) + "_id"
+				}
+			case belongsToTag:
+				if isSlice {
+					return assocs, errors.Errorf("belongs_to association %s.%s can not be a slice", t.Name(), field.Name)
+				}
+				if a.FkID == "" {
+					a.FkID = inflect.Underscore(field.Name) + "_id"
+				}
+			case manyToManyTag:
+				if !isSlice {
+					return assocs, errors.Errorf("many_to_many association %s.%s must be a slice", t.Name(), field.Name)
+				}
+				if a.Table == "" {
+					return assocs, errors.Errorf("many_to_many association %s.%s requires join table name", t.Name(), field.Name)
+				}
+				if a.FkID == "" {
+					a.FkID = inflect.Underscore(t.Name()) + "_id"
+				}
+				if a.PrimaryID == "" {
+					a.PrimaryID = inflect.Underscore(ft.Name()) + "_id"
+				}
+			}
+
+			assocs[field.Name] = a
+		}
+	}
+	return assocs, nil
+}
+
+// fieldByColumn finds struct field mapped to given column using db tag
+func fieldByColumn(v reflect.Value, column string) (reflect.Value, error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get(modelTag) == column {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, errors.Errorf("%s does not have a field with column %s", t.Name(), column)
+}
+
+// keyOf converts field value to a string used to match related rows.
+// Empty string is returned for NULL values.
+func keyOf(v reflect.Value) string {
+	i := v.Interface()
+	if dv, ok := i.(driver.Valuer); ok {
+		val, err := dv.Value()
+		if err != nil || val == nil {
+			return ""
+		}
+		i = val
+	}
+	if b, ok := i.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(i)
+}
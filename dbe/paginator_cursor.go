@@ -0,0 +1,318 @@
+package dbe
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/flow/defaults"
+)
+
+// PaginatorCursorKey is the query parameter holding the cursor of the current page
+var PaginatorCursorKey = "cursor"
+
+// cursor directions
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// CursorPaginator is a type used to represent keyset pagination of records
+// from the database. Records are paged using values of query order columns,
+// so pages stay stable when records are added or removed.
+type CursorPaginator struct {
+	// Cursor of the current page, empty for the first page
+	Cursor string `json:"cursor"`
+	// Number of results you want per page
+	PerPage int `json:"per_page"`
+	// Cursor of the next page, empty if there are no more records
+	Next string `json:"next,omitempty"`
+	// Cursor of the previous page, empty if this is the first page
+	Prev string `json:"prev,omitempty"`
+	// Total records returns, will be <= PerPage
+	CurrentEntriesSize int `json:"current_entries_size"`
+}
+
+// NewCursorPaginator returns a new `CursorPaginator` value with the appropriate
+// defaults set.
+func NewCursorPaginator(cursor string, perPage int) *CursorPaginator {
+	if perPage < 1 {
+		perPage = PaginatorPerPageDefault
+	}
+	return &CursorPaginator{Cursor: cursor, PerPage: perPage}
+}
+
+// NewCursorPaginatorFromParams takes an interface of type `PaginationParams`,
+// the `url.Values` type works great with this interface, and returns
+// a new `CursorPaginator` based on the params or `PaginatorCursorKey` and
+// `PaginatorPerPageKey`.
+func NewCursorPaginatorFromParams(params PaginationParams) *CursorPaginator {
+	perPage := defaults.String(params.Get(PaginatorPerPageKey), strconv.Itoa(PaginatorPerPageDefault))
+
+	pp, err := strconv.Atoi(perPage)
+	if err != nil {
+		pp = PaginatorPerPageDefault
+	}
+	return NewCursorPaginator(params.Get(PaginatorCursorKey), pp)
+}
+
+// PaginateAfter paginates records returned from the database using keyset
+// pagination. Query order clauses are used as the key, `id` is appended
+// to them to make the order unique. Ordering by nullable columns is not
+// supported, as keyset comparison skips records with NULL values.
+//
+//	q = q.Order("created_at desc").PaginateAfter(cursor, 15)
+//	q.All(&[]User{})
+//	q.CursorPaginator.Next
+func (q *Query) PaginateAfter(cursor string, perPage int) *Query {
+	q.CursorPaginator = NewCursorPaginator(cursor, perPage)
+	return q
+}
+
+// PaginateAfterFromParams paginates records returned from the database using keyset pagination.
+//
+//	q = q.PaginateAfterFromParams(req.URL.Query())
+//	q.All(&[]User{})
+//	q.CursorPaginator
+func (q *Query) PaginateAfterFromParams(params PaginationParams) *Query {
+	q.CursorPaginator = NewCursorPaginatorFromParams(params)
+	return q
+}
+
+// cursorKey is a column used to order and page records
+type cursorKey struct {
+	Column string
+	Desc   bool
+}
+
+// cursorData holds values of the cursor encoded into opaque string
+type cursorData struct {
+	Direction string
+	Values    []interface{}
+}
+
+// allAfter retrieves a single page of records using keyset pagination
+func (q *Query) allAfter(models interface{}) error {
+	m := &Model{Value: models}
+	p := q.CursorPaginator
+
+	cur, err := decodeCursor(p.Cursor)
+	if err != nil {
+		return err
+	}
+	back := cur.Direction == cursorPrev
+
+	keys := q.cursorKeys(m)
+	if err := checkCursorKeys(models, keys); err != nil {
+		return err
+	}
+
+	tmpQuery := NewQuery(q.Connection)
+	q.Clone(tmpQuery)
+	tmpQuery.CursorPaginator = nil
+	tmpQuery.Paginator = nil
+	tmpQuery.orderClauses = Clauses{}
+	tmpQuery.limitResults = p.PerPage + 1
+
+	if len(cur.Values) > 0 {
+		if len(cur.Values) != len(keys) {
+			return errors.New("cursor does not match query order")
+		}
+		tmpQuery.Filter(keysetCondition(keys, cur.Values, back))
+	}
+
+	for _, k := range keys {
+		dir := "ASC"
+		if k.Desc != back {
+			dir = "DESC"
+		}
+		tmpQuery.Order(fmt.Sprintf("%s %s", k.Column, dir))
+	}
+
+	sql, args := tmpQuery.ToSQL(m)
+	err = q.read(func(s Store) error {
+		return s.SelectContext(q.Connection.Context(), m.Value, sql, args...)
+	})
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(models).Elem()
+	more := v.Len() > p.PerPage
+	if more {
+		v.Set(v.Slice(0, p.PerPage))
+	}
+	if back {
+		swap := reflect.Swapper(v.Interface())
+		for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	p.CurrentEntriesSize = v.Len()
+	p.Next, p.Prev = "", ""
+	if v.Len() > 0 {
+		hasNext, hasPrev := more, len(cur.Values) > 0
+		if back {
+			hasNext, hasPrev = len(cur.Values) > 0, more
+		}
+		if hasNext {
+			p.Next, err = encodeCursor(cursorNext, keys, v.Index(v.Len()-1))
+			if err != nil {
+				return err
+			}
+		}
+		if hasPrev {
+			p.Prev, err = encodeCursor(cursorPrev, keys, v.Index(0))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if q.eager {
+		return q.eagerLoad(m.Value)
+	}
+	return nil
+}
+
+// cursorKeys parses query order clauses into keys used to page records
+func (q *Query) cursorKeys(m *Model) []cursorKey {
+	keys := []cursorKey{}
+	hasID := false
+	for _, oc := range q.orderClauses {
+		for _, part := range strings.Split(oc.Fragment, ",") {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+			k := cursorKey{
+				Column: fields[0],
+				Desc:   len(fields) > 1 && strings.ToUpper(fields[1]) == "DESC",
+			}
+			if columnName(k.Column) == "id" {
+				hasID = true
+			}
+			keys = append(keys, k)
+		}
+	}
+	if !hasID {
+		asName := strings.Replace(m.TableName(), ".", "_", -1)
+		keys = append(keys, cursorKey{Column: asName + ".id"})
+	}
+	return keys
+}
+
+// checkCursorKeys rejects keys mapped to fields of nullable types,
+// e.g. pointers, nulls or sql.Null types
+func checkCursorKeys(models interface{}, keys []cursorKey) error {
+	t := reflect.TypeOf(models).Elem().Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for _, k := range keys {
+		name := columnName(k.Column)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get(modelTag) == name && nullable(f.Type) {
+				return errors.Errorf("can't paginate using cursor ordered by nullable column %s", k.Column)
+			}
+		}
+	}
+	return nil
+}
+
+// nullable reports whether values of given type may be NULL
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return true
+	case reflect.Struct:
+		f, ok := t.FieldByName("Valid")
+		return ok && f.Type.Kind() == reflect.Bool && reflect.PtrTo(t).Implements(valuerType)
+	}
+	return false
+}
+
+// keysetCondition creates condition selecting records after (or before) given key values
+func keysetCondition(keys []cursorKey, values []interface{}, back bool) Condition {
+	conds := []Condition{}
+	for i, k := range keys {
+		and := []Condition{}
+		for j := 0; j < i; j++ {
+			and = append(and, Eq(keys[j].Column, values[j]))
+		}
+		op := ">"
+		if k.Desc != back {
+			op = "<"
+		}
+		and = append(and, compare(k.Column, op, values[i]))
+		conds = append(conds, And(and...))
+	}
+	return Or(conds...)
+}
+
+// encodeCursor creates opaque cursor from key values of given record
+func encodeCursor(direction string, keys []cursorKey, record reflect.Value) (string, error) {
+	if record.Kind() == reflect.Ptr {
+		record = record.Elem()
+	}
+
+	cur := cursorData{Direction: direction}
+	for _, k := range keys {
+		f, err := fieldByColumn(record, columnName(k.Column))
+		if err != nil {
+			return "", errors.Wrap(err, "could not create cursor")
+		}
+		v, err := driver.DefaultParameterConverter.ConvertValue(f.Interface())
+		if err != nil {
+			return "", errors.Wrap(err, "could not create cursor")
+		}
+		cur.Values = append(cur.Values, v)
+	}
+
+	var buff bytes.Buffer
+	if err := gob.NewEncoder(&buff).Encode(cur); err != nil {
+		return "", errors.Wrap(err, "could not create cursor")
+	}
+	return base64.RawURLEncoding.EncodeToString(buff.Bytes()), nil
+}
+
+// decodeCursor reads key values from opaque cursor
+func decodeCursor(s string) (cursorData, error) {
+	cur := cursorData{Direction: cursorNext}
+	if s == "" {
+		return cur, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, errors.Wrap(err, "invalid cursor")
+	}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&cur); err != nil {
+		return cur, errors.Wrap(err, "invalid cursor")
+	}
+	return cur, nil
+}
+
+// columnName strips table name from column
+func columnName(column string) string {
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		return column[i+1:]
+	}
+	return column
+}
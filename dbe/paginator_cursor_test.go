@@ -0,0 +1,87 @@
+package dbe
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sedind/flow/dbe/nulls"
+)
+
+func TestQuery_PaginateAfter(t *testing.T) {
+	c := newTestConnection(t)
+
+	users := []testUser{}
+	for i := 0; i < 7; i++ {
+		users = append(users, testUser{Name: fmt.Sprintf("n%d", i%3)})
+	}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+
+	// names are not unique, so pages are ordered by name and id
+	seen := []int{}
+	cursor := ""
+	var last *CursorPaginator
+	for page := 0; page < 3; page++ {
+		found := []*testUser{}
+		q := c.Query().Order("name desc").PaginateAfterFromParams(url.Values{"cursor": {cursor}, "per_page": {"3"}})
+		if err := q.All(&found); err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range found {
+			seen = append(seen, u.ID)
+		}
+
+		last = q.CursorPaginator
+		cursor = q.CursorPaginator.Next
+		if cursor == "" {
+			break
+		}
+	}
+	if fmt.Sprint(seen) != "[3 6 2 5 1 4 7]" {
+		t.Fatalf("expected every user once, got %v", seen)
+	}
+	if last.Next != "" {
+		t.Fatalf("expected no next page, got %s", last.Next)
+	}
+
+	found := []testUser{}
+	q := c.Query().Order("name desc").PaginateAfter(last.Prev, 3)
+	if err := q.All(&found); err != nil {
+		t.Fatal(err)
+	}
+	prev := []int{}
+	for _, u := range found {
+		prev = append(prev, u.ID)
+	}
+	if fmt.Sprint(prev) != fmt.Sprint(seen[3:6]) {
+		t.Fatalf("expected previous page %v, got %v", seen[3:6], prev)
+	}
+}
+
+// testEvent is model with nullable columns
+type testEvent struct {
+	ID       int        `db:"id"`
+	StartsAt nulls.Time `db:"starts_at"`
+	EndsAt   *time.Time `db:"ends_at"`
+}
+
+func (testEvent) TableName() string { return "events" }
+
+func TestQuery_PaginateAfterNullable(t *testing.T) {
+	c := newTestConnection(t, "CREATE TABLE events (id INTEGER PRIMARY KEY, starts_at DATETIME NULL, ends_at DATETIME NULL)")
+
+	for _, order := range []string{"starts_at", "starts_at desc", "events.ends_at asc", "id, ends_at desc"} {
+		err := c.Query().Order(order).PaginateAfter("", 3).All(&[]testEvent{})
+		if err == nil || !strings.Contains(err.Error(), "nullable column") {
+			t.Errorf("%s: expected nullable column error, got %v", order, err)
+		}
+	}
+
+	if err := c.Query().Order("id desc").PaginateAfter("", 3).All(&[]*testEvent{}); err != nil {
+		t.Fatal(err)
+	}
+}
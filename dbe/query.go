@@ -1,6 +1,7 @@
 package dbe
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -11,18 +12,22 @@ var inRegex = regexp.MustCompile(`(?i)in\s*\(\s*\?\s*\)`)
 // Query is the main value that is used to build up a query
 // to be executed against the `Connection`.
 type Query struct {
-	RawSQL        *Clause
-	limitResults  int
-	eager         bool
-	eagerFields   []string
-	whereClauses  Clauses
-	orderClauses  Clauses
-	fromClauses   FromClauses
-	joinClauses   JoinClauses
-	groupClauses  GroupClauses
-	havingClauses HavingClauses
-	Paginator     *Paginator
-	Connection    *Connection
+	RawSQL          *Clause
+	limitResults    int
+	eager           bool
+	eagerFields     []string
+	deletedScope    int
+	unscoped        bool
+	usePrimary      bool
+	whereClauses    Clauses
+	orderClauses    Clauses
+	fromClauses     FromClauses
+	joinClauses     JoinClauses
+	groupClauses    GroupClauses
+	havingClauses   HavingClauses
+	Paginator       *Paginator
+	CursorPaginator *CursorPaginator
+	Connection      *Connection
 }
 
 // Query Creates new Empty Query
@@ -44,6 +49,11 @@ func (q *Query) Clone(targetQ *Query) {
 	targetQ.RawSQL = &rawSQL
 
 	targetQ.limitResults = q.limitResults
+	targetQ.eager = q.eager
+	targetQ.eagerFields = q.eagerFields
+	targetQ.deletedScope = q.deletedScope
+	targetQ.unscoped = q.unscoped
+	targetQ.usePrimary = q.usePrimary
 	targetQ.whereClauses = q.whereClauses
 	targetQ.orderClauses = q.orderClauses
 	targetQ.fromClauses = q.fromClauses
@@ -56,12 +66,26 @@ func (q *Query) Clone(targetQ *Query) {
 		targetQ.Paginator = &paginator
 	}
 
+	if q.CursorPaginator != nil {
+		paginator := *q.CursorPaginator
+		targetQ.CursorPaginator = &paginator
+	}
+
 	if q.Connection != nil {
 		connection := *q.Connection
 		targetQ.Connection = &connection
 	}
 }
 
+// WithContext sets context used to execute the query.
+// Query execution is cancelled when ctx is done.
+//
+//	q.WithContext(r.Context()).Where("name = ?", "Irfan").All(&[]User{})
+func (q *Query) WithContext(ctx context.Context) *Query {
+	q.Connection = q.Connection.WithContext(ctx)
+	return q
+}
+
 // Raw will override the query building feature, and will use
 // whatever query you want to execute against the `Connection`. You can continue
 // to use the `?` argument syntax.
@@ -78,10 +102,6 @@ func (q *Query) Raw(stmt string, args ...interface{}) *Query {
 // 	q.Where("id = ?", 1)
 // 	q.Where("id in (?)", 1, 2, 3)
 func (q *Query) Where(stmt string, args ...interface{}) *Query {
-	if q.RawSQL.Fragment != "" {
-		fmt.Println("Warning: Query is setup to use raw SQL")
-		return q
-	}
 	if inRegex.MatchString(stmt) {
 		var inq []string
 		for i := 0; i < len(args); i++ {
@@ -90,7 +110,16 @@ func (q *Query) Where(stmt string, args ...interface{}) *Query {
 		qs := fmt.Sprintf("(%s)", strings.Join(inq, ","))
 		stmt = strings.Replace(stmt, "(?)", qs, 1)
 	}
-	q.whereClauses = append(q.whereClauses, Clause{stmt, args})
+	return q.where(Clause{stmt, args})
+}
+
+// where appends given clause to the where clause of the query
+func (q *Query) where(clause Clause) *Query {
+	if q.RawSQL.Fragment != "" {
+		fmt.Println("Warning: Query is setup to use raw SQL")
+		return q
+	}
+	q.whereClauses = append(q.whereClauses, clause)
 	return q
 }
 
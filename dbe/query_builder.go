@@ -2,7 +2,6 @@ package dbe
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -64,6 +63,8 @@ func (qb *QueryBuilder) compile() {
 }
 
 func (qb *QueryBuilder) buildSelectSQL() string {
+	qb.applyDefaultScope()
+
 	cols := qb.buildColumns()
 
 	fc := qb.buildfromClauses()
@@ -79,6 +80,31 @@ func (qb *QueryBuilder) buildSelectSQL() string {
 	return sql
 }
 
+// applyDefaultScope applies default scope of the model
+// unless query is unscoped
+func (qb *QueryBuilder) applyDefaultScope() {
+	if qb.Query.unscoped || qb.Model == nil {
+		return
+	}
+
+	s, ok := qb.Model.defaultScoper()
+	if !ok {
+		return
+	}
+
+	// copy clauses so scope does not change original query
+	q := qb.Query
+	q.whereClauses = append(Clauses{}, q.whereClauses...)
+	q.orderClauses = append(Clauses{}, q.orderClauses...)
+	q.fromClauses = append(FromClauses{}, q.fromClauses...)
+	q.joinClauses = append(JoinClauses{}, q.joinClauses...)
+	q.groupClauses = append(GroupClauses{}, q.groupClauses...)
+	q.havingClauses = append(HavingClauses{}, q.havingClauses...)
+	q.unscoped = true
+
+	qb.Query = *s.DefaultScope(&q)
+}
+
 func (qb *QueryBuilder) buildfromClauses() FromClauses {
 	models := []*Model{
 		qb.Model,
@@ -86,14 +112,9 @@ func (qb *QueryBuilder) buildfromClauses() FromClauses {
 
 	fc := qb.Query.fromClauses
 	for _, m := range models {
-		tableName := m.TableName()
-		asName := m.As
-		if asName == "" {
-			asName = strings.Replace(tableName, ".", "_", -1)
-		}
 		fc = append(fc, FromClause{
-			From: tableName,
-			As:   asName,
+			From: m.TableName(),
+			As:   m.asName(),
 		})
 	}
 
@@ -102,6 +123,15 @@ func (qb *QueryBuilder) buildfromClauses() FromClauses {
 
 func (qb *QueryBuilder) buildWhereClauses(sql string) string {
 	wc := qb.Query.whereClauses
+	if qb.Model != nil && qb.Model.softDeletable() {
+		wc = append(Clauses{}, wc...)
+		switch qb.Query.deletedScope {
+		case scopeNotDeleted:
+			wc = append(wc, Clause{fmt.Sprintf("%s.deleted_at IS NULL", qb.Model.asName()), []interface{}{}})
+		case scopeOnlyDeleted:
+			wc = append(wc, Clause{fmt.Sprintf("%s.deleted_at IS NOT NULL", qb.Model.asName()), []interface{}{}})
+		}
+	}
 	if len(wc) > 0 {
 		sql = fmt.Sprintf("%s WHERE %s", sql, wc.Join(" AND "))
 		for _, arg := range wc.Args() {
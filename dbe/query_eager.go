@@ -0,0 +1,273 @@
+package dbe
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Eager will enable eager loading of model associations defined with
+// `has_many`, `belongs_to` and `many_to_many` struct tags.
+// Nested associations are separated by dot. If no fields are provided
+// all associations of the model will be loaded.
+//
+//	q.Eager("Orders", "Orders.Items").All(&[]User{})
+func (q *Query) Eager(fields ...string) *Query {
+	q.eager = true
+	q.eagerFields = append(q.eagerFields, fields...)
+	return q
+}
+
+// eagerLoad loads associations for already loaded model(s)
+func (q *Query) eagerLoad(model interface{}) error {
+	return q.read(func(s Store) error {
+		c := q.Connection.copy()
+		c.Store = s
+		return eagerLoad(c, reflect.ValueOf(model), q.eagerFields)
+	})
+}
+
+// eagerLoad loads given associations for model or slice of models held in v.
+// Each association is loaded using a single batched query.
+func eagerLoad(c *Connection, v reflect.Value, fields []string) error {
+	owners := eagerOwners(v)
+	if len(owners) == 0 {
+		return nil
+	}
+
+	assocs, err := (&Model{Value: owners[0].Addr().Interface()}).associations()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	names := []string{}
+	nested := map[string][]string{}
+	if len(fields) == 0 {
+		for name := range assocs {
+			names = append(names, name)
+		}
+	}
+	for _, f := range fields {
+		parts := strings.SplitN(f, ".", 2)
+		if _, ok := nested[parts[0]]; !ok {
+			names = append(names, parts[0])
+			nested[parts[0]] = []string{}
+		}
+		if len(parts) > 1 {
+			nested[parts[0]] = append(nested[parts[0]], parts[1])
+		}
+	}
+
+	for _, name := range names {
+		a, ok := assocs[name]
+		if !ok {
+			return errors.Errorf("could not find association %s on %s", name, owners[0].Type().Name())
+		}
+
+		switch a.Kind {
+		case hasManyTag:
+			err = eagerHasMany(c, a, owners, nested[name])
+		case belongsToTag:
+			err = eagerBelongsTo(c, a, owners, nested[name])
+		case manyToManyTag:
+			err = eagerManyToMany(c, a, owners, nested[name])
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not load association %s", name)
+		}
+	}
+	return nil
+}
+
+// eagerOwners returns addressable struct values held in v
+func eagerOwners(v reflect.Value) []reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	owners := []reflect.Value{}
+	switch v.Kind() {
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i)
+			if e.Kind() == reflect.Ptr {
+				if e.IsNil() {
+					continue
+				}
+				e = e.Elem()
+			}
+			owners = append(owners, e)
+		}
+	case reflect.Struct:
+		owners = append(owners, v)
+	}
+	return owners
+}
+
+// eagerTargets loads rows of associated model where column matches one of ids
+// and eager loads their nested associations
+func eagerTargets(c *Connection, a association, table string, column string, ids []interface{}, nested []string) (reflect.Value, error) {
+	targets := reflect.New(reflect.SliceOf(a.target()))
+
+	m := &Model{Value: targets.Interface(), tableName: table}
+	sql, args := NewQuery(c).Where(fmt.Sprintf("%s in (?)", column), ids...).ToSQL(m)
+	err := c.Store.SelectContext(c.Context(), m.Value, sql, args...)
+	if err != nil {
+		return targets, errors.WithStack(err)
+	}
+
+	if len(nested) > 0 {
+		err = eagerLoad(c, targets, nested)
+	}
+	return targets.Elem(), err
+}
+
+// eagerHasMany loads rows referencing owners through fk_id column
+func eagerHasMany(c *Connection, a association, owners []reflect.Value, nested []string) error {
+	ids := []interface{}{}
+	for _, o := range owners {
+		ids = append(ids, (&Model{Value: o.Addr().Interface()}).ID())
+	}
+
+	targets, err := eagerTargets(c, a, a.Table, a.FkID, ids, nested)
+	if err != nil {
+		return err
+	}
+
+	groups := map[string][]reflect.Value{}
+	for i := 0; i < targets.Len(); i++ {
+		t := targets.Index(i)
+		fk, err := fieldByColumn(t, a.FkID)
+		if err != nil {
+			return err
+		}
+		key := keyOf(fk)
+		groups[key] = append(groups[key], t)
+	}
+
+	for _, o := range owners {
+		key := keyOf(reflect.ValueOf((&Model{Value: o.Addr().Interface()}).ID()))
+		field := o.FieldByIndex(a.Field.Index)
+		slice := reflect.MakeSlice(field.Type(), 0, len(groups[key]))
+		for _, t := range groups[key] {
+			slice = reflect.Append(slice, eagerElem(t, field.Type().Elem()))
+		}
+		field.Set(slice)
+	}
+	return nil
+}
+
+// eagerBelongsTo loads rows referenced by owners fk_id column
+func eagerBelongsTo(c *Connection, a association, owners []reflect.Value, nested []string) error {
+	ids := []interface{}{}
+	keys := map[string]bool{}
+	for _, o := range owners {
+		fk, err := fieldByColumn(o, a.FkID)
+		if err != nil {
+			return err
+		}
+		key := keyOf(fk)
+		if key == "" || keys[key] {
+			continue
+		}
+		keys[key] = true
+		ids = append(ids, fk.Interface())
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	targets, err := eagerTargets(c, a, a.Table, "id", ids, nested)
+	if err != nil {
+		return err
+	}
+
+	byID := map[string]reflect.Value{}
+	for i := 0; i < targets.Len(); i++ {
+		t := targets.Index(i)
+		byID[keyOf(reflect.ValueOf((&Model{Value: t.Addr().Interface()}).ID()))] = t
+	}
+
+	for _, o := range owners {
+		fk, _ := fieldByColumn(o, a.FkID)
+		if t, ok := byID[keyOf(fk)]; ok {
+			field := o.FieldByIndex(a.Field.Index)
+			field.Set(eagerElem(t, field.Type()))
+		}
+	}
+	return nil
+}
+
+// eagerManyToMany loads rows related to owners through join table
+func eagerManyToMany(c *Connection, a association, owners []reflect.Value, nested []string) error {
+	ids := []interface{}{}
+	for _, o := range owners {
+		ids = append(ids, (&Model{Value: o.Addr().Interface()}).ID())
+	}
+
+	query := fmt.Sprintf("SELECT %s AS owner_id, %s AS target_id FROM %s WHERE %s in (?)", a.FkID, a.PrimaryID, a.Table, a.FkID)
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	query = c.Dialect.TranslateSQL(query)
+	rows := []joinRow{}
+	err = c.Store.SelectContext(c.Context(), &rows, query, args...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	targetIDs := []interface{}{}
+	seen := map[string]bool{}
+	for _, r := range rows {
+		if !seen[r.TargetID] {
+			seen[r.TargetID] = true
+			targetIDs = append(targetIDs, r.TargetID)
+		}
+	}
+
+	byID := map[string]reflect.Value{}
+	if len(targetIDs) > 0 {
+		targets, err := eagerTargets(c, a, "", "id", targetIDs, nested)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < targets.Len(); i++ {
+			t := targets.Index(i)
+			byID[keyOf(reflect.ValueOf((&Model{Value: t.Addr().Interface()}).ID()))] = t
+		}
+	}
+
+	for _, o := range owners {
+		key := keyOf(reflect.ValueOf((&Model{Value: o.Addr().Interface()}).ID()))
+		field := o.FieldByIndex(a.Field.Index)
+		slice := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, r := range rows {
+			if t, ok := byID[r.TargetID]; ok && r.OwnerID == key {
+				slice = reflect.Append(slice, eagerElem(t, field.Type().Elem()))
+			}
+		}
+		field.Set(slice)
+	}
+	return nil
+}
+
+// eagerElem converts loaded struct value to type expected by association field
+func eagerElem(v reflect.Value, t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.Ptr {
+		return v.Addr()
+	}
+	return v
+}
+
+// joinRow is helper struct for many_to_many join table query
+type joinRow struct {
+	OwnerID  string `db:"owner_id"`
+	TargetID string `db:"target_id"`
+}
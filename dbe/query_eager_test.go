@@ -0,0 +1,73 @@
+package dbe
+
+import "testing"
+
+type testAuthor struct {
+	ID    int        `db:"id"`
+	Name  string     `db:"name"`
+	Books []testBook `db:"-" has_many:"books" fk_id:"author_id"`
+}
+
+func (testAuthor) TableName() string { return "authors" }
+
+type testBook struct {
+	ID       int         `db:"id"`
+	AuthorID int         `db:"author_id"`
+	Author   *testAuthor `db:"-" belongs_to:"authors" fk_id:"author_id"`
+	Tags     []*testTag  `db:"-" many_to_many:"book_tags" fk_id:"book_id" primary_id:"tag_id"`
+}
+
+func (testBook) TableName() string { return "books" }
+
+type testTag struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func (testTag) TableName() string { return "tags" }
+
+func TestQuery_Eager(t *testing.T) {
+	c := newTestConnection(t,
+		"CREATE TABLE authors (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE books (id INTEGER PRIMARY KEY, author_id INTEGER)",
+		"CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE book_tags (book_id INTEGER, tag_id INTEGER)",
+		"INSERT INTO authors VALUES (1, 'a'), (2, 'b'), (3, 'c')",
+		"INSERT INTO books VALUES (1, 1), (2, 1), (3, 2)",
+		"INSERT INTO tags VALUES (1, 'x'), (2, 'y')",
+		"INSERT INTO book_tags VALUES (1, 1), (1, 2), (3, 2)",
+	)
+
+	authors := []testAuthor{}
+	err := c.Query().Eager("Books", "Books.Tags", "Books.Author").Order("id").All(&authors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	books := []int{}
+	for _, a := range authors {
+		books = append(books, len(a.Books))
+	}
+	if len(books) != 3 || books[0] != 2 || books[1] != 1 || books[2] != 0 {
+		t.Fatalf("expected 2, 1 and 0 books of authors, got %v", books)
+	}
+
+	book := authors[0].Books[0]
+	if len(book.Tags) != 2 {
+		t.Fatalf("expected 2 tags of book 1, got %d", len(book.Tags))
+	}
+	if book.Author == nil || book.Author.Name != "a" {
+		t.Fatalf("expected author a of book 1, got %+v", book.Author)
+	}
+	if tags := authors[1].Books[0].Tags; len(tags) != 1 || tags[0].Name != "y" {
+		t.Fatalf("expected tag y of book 3, got %+v", tags)
+	}
+
+	b := testBook{}
+	if err := c.Query().Eager().Find(&b, 3); err != nil {
+		t.Fatal(err)
+	}
+	if b.Author == nil || b.Author.Name != "b" {
+		t.Fatalf("expected all associations to be loaded, got %+v", b)
+	}
+}
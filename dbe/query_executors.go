@@ -14,16 +14,14 @@ var rLimit = regexp.MustCompile("(?i)(limit [0-9]+)$")
 func (q *Query) Exec() error {
 
 	sql, args := q.ToSQL(nil)
-	Logger.Info(fmt.Sprintf("%s | %s", sql, args))
-	_, err := q.Connection.Store.Exec(sql, args...)
+	_, err := q.Connection.Store.ExecContext(q.Connection.Context(), sql, args...)
 	return err
 }
 
 // ExecWithCount Execute and count
 func (q *Query) ExecWithCount() (int64, error) {
 	sql, args := q.ToSQL(nil)
-	Logger.Info(fmt.Sprintf("%s | %s", sql, args))
-	result, err := q.Connection.Store.Exec(sql, args...)
+	result, err := q.Connection.Store.ExecContext(q.Connection.Context(), sql, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -37,9 +35,13 @@ func (q *Query) First(model interface{}) error {
 	m := &Model{Value: model}
 	q.Limit(1)
 	sql, args := q.ToSQL(m)
-	Logger.Info(fmt.Sprintf("%s | %s", sql, args))
-
-	return q.Connection.Store.Get(m.Value, sql, args...)
+	err := q.read(func(s Store) error {
+		return s.GetContext(q.Connection.Context(), m.Value, sql, args...)
+	})
+	if err == nil && q.eager {
+		err = q.eagerLoad(m.Value)
+	}
+	return err
 }
 
 // Last record of the model in the database that matches the query.
@@ -50,8 +52,13 @@ func (q *Query) Last(model interface{}) error {
 	q.Order("id DESC")
 	q.Limit(1)
 	sql, args := q.ToSQL(m)
-	Logger.Info(fmt.Sprintf("%s | %s", sql, args))
-	return q.Connection.Store.Get(m.Value, sql, args...)
+	err := q.read(func(s Store) error {
+		return s.GetContext(q.Connection.Context(), m.Value, sql, args...)
+	})
+	if err == nil && q.eager {
+		err = q.eagerLoad(m.Value)
+	}
+	return err
 }
 
 // Find the first record of the model in the database with a particular id.
@@ -76,10 +83,15 @@ func (q *Query) Find(model interface{}, id interface{}) error {
 //
 //	q.Where("name = ?", "Irfan").All(&[]User{})
 func (q *Query) All(models interface{}) error {
+	if q.CursorPaginator != nil {
+		return q.allAfter(models)
+	}
+
 	m := &Model{Value: models}
 	sql, args := q.ToSQL(m)
-	Logger.Info(fmt.Sprintf("%s | %s", sql, args))
-	err := q.Connection.Store.Select(m.Value, sql, args...)
+	err := q.read(func(s Store) error {
+		return s.SelectContext(q.Connection.Context(), m.Value, sql, args...)
+	})
 	if err == nil && q.Paginator != nil {
 		ct, err := q.Count(models)
 		if err == nil {
@@ -92,6 +104,9 @@ func (q *Query) All(models interface{}) error {
 			}
 		}
 	}
+	if err == nil && q.eager {
+		err = q.eagerLoad(m.Value)
+	}
 	return err
 }
 
@@ -139,8 +154,9 @@ func (q Query) CountByField(model interface{}, field string) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	Logger.Info(fmt.Sprintf("%s | %s", countQuery, args))
-	err = q.Connection.Store.Get(res, countQuery, args...)
+	err = q.read(func(s Store) error {
+		return s.GetContext(q.Connection.Context(), res, countQuery, args...)
+	})
 	if err != nil {
 		return 0, err
 	}
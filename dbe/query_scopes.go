@@ -0,0 +1,63 @@
+package dbe
+
+import "reflect"
+
+// ScopeFunc applies reusable set of clauses to the query
+//
+//	func Active(q *dbe.Query) *dbe.Query {
+//		return q.Where("active = ?", true)
+//	}
+type ScopeFunc func(q *Query) *Query
+
+// DefaultScoper interface allows model to define scope which is
+// automatically applied to every query built for the model.
+// Use `Query.Unscoped` to skip it.
+//
+//	func (User) DefaultScope(q *dbe.Query) *dbe.Query {
+//		return q.Where("users.tenant_id = ?", tenantID)
+//	}
+type DefaultScoper interface {
+	DefaultScope(q *Query) *Query
+}
+
+// Scope applies given scopes to the query.
+//
+//	q.Scope(Active, VisibleTo(user)).All(&[]Post{})
+func (q *Query) Scope(scopes ...ScopeFunc) *Query {
+	for _, scope := range scopes {
+		q = scope(q)
+	}
+	return q
+}
+
+// Unscoped disables default scope of the model for the query.
+func (q *Query) Unscoped() *Query {
+	q.unscoped = true
+	return q
+}
+
+// defaultScoper returns default scope of the model if it implements
+// DefaultScoper interface. For slices elements are checked.
+func (m *Model) defaultScoper() (DefaultScoper, bool) {
+	if s, ok := m.Value.(DefaultScoper); ok {
+		return s, true
+	}
+
+	t := reflect.TypeOf(m.Value)
+	if t == nil {
+		return nil, false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	el := t.Elem()
+	if el.Kind() == reflect.Ptr {
+		el = el.Elem()
+	}
+	s, ok := reflect.New(el).Interface().(DefaultScoper)
+	return s, ok
+}
@@ -0,0 +1,78 @@
+package dbe
+
+import "testing"
+
+// testVisibleUser is model of users table hiding user named hidden by default
+type testVisibleUser struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func (testVisibleUser) TableName() string { return "users" }
+
+func (testVisibleUser) DefaultScope(q *Query) *Query {
+	return q.Where("name <> ?", "hidden")
+}
+
+func namedLike(pattern string) ScopeFunc {
+	return func(q *Query) *Query {
+		return q.Where("name LIKE ?", pattern)
+	}
+}
+
+func TestQuery_Scope(t *testing.T) {
+	c := newTestConnection(t)
+
+	users := []testUser{{Name: "ann"}, {Name: "anna"}, {Name: "bob"}, {Name: "hidden"}}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+
+	q := c.Query().Scope(namedLike("ann%"), namedLike("%a"))
+	found := []testUser{}
+	if err := q.All(&found); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Name != "anna" {
+		t.Fatalf("expected user anna, got %+v", found)
+	}
+}
+
+func TestQuery_DefaultScope(t *testing.T) {
+	c := newTestConnection(t)
+
+	users := []testUser{{Name: "ann"}, {Name: "hidden"}}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+
+	q := c.Query()
+	for i := 0; i < 2; i++ {
+		n, err := q.Count(&testVisibleUser{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Fatalf("expected 1 visible user, got %d", n)
+		}
+	}
+	if len(q.whereClauses) != 0 {
+		t.Fatalf("expected default scope not to change query, got %v", q.whereClauses)
+	}
+
+	visible := []*testVisibleUser{}
+	if err := c.Query().All(&visible); err != nil {
+		t.Fatal(err)
+	}
+	if len(visible) != 1 || visible[0].Name != "ann" {
+		t.Fatalf("expected user ann, got %+v", visible)
+	}
+
+	all := []testVisibleUser{}
+	if err := c.Query().Unscoped().All(&all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 unscoped users, got %d", len(all))
+	}
+}
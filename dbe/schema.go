@@ -0,0 +1,46 @@
+package dbe
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaColumn describes column of a database table
+type SchemaColumn struct {
+	Name     string `db:"name"`
+	Type     string `db:"type"`
+	Nullable bool   `db:"nullable"`
+}
+
+// TableSchema returns columns of given table in the order they are defined.
+// No columns are returned when table does not exist.
+func (c *Connection) TableSchema(table string) ([]SchemaColumn, error) {
+	cols := []SchemaColumn{}
+
+	var err error
+	switch c.Details.Dialect {
+	case "postgres":
+		err = c.Store.SelectContext(c.Context(), &cols, "SELECT column_name AS name, data_type AS type, is_nullable = 'YES' AS nullable FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position", table)
+	case "sqlite3":
+		rows := []struct {
+			CID     int         `db:"cid"`
+			Name    string      `db:"name"`
+			Type    string      `db:"type"`
+			NotNull bool        `db:"notnull"`
+			Default interface{} `db:"dflt_value"`
+			PK      int         `db:"pk"`
+		}{}
+		err = c.Store.SelectContext(c.Context(), &rows, fmt.Sprintf("PRAGMA table_info(%s)", table))
+		for _, r := range rows {
+			cols = append(cols, SchemaColumn{Name: r.Name, Type: r.Type, Nullable: !r.NotNull && r.PK == 0})
+		}
+	default:
+		err = c.Store.SelectContext(c.Context(), &cols, "SELECT column_name AS name, column_type AS type, is_nullable = 'YES' AS nullable FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", table)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read schema of %s table", table)
+	}
+	return cols, nil
+}
@@ -0,0 +1,112 @@
+package dbe
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/flow/dbe/nulls"
+)
+
+// soft delete query scopes
+const (
+	scopeNotDeleted = iota
+	scopeWithDeleted
+	scopeOnlyDeleted
+)
+
+// WithDeleted includes soft deleted records in query results.
+//
+//	q.WithDeleted().All(&[]User{})
+func (q *Query) WithDeleted() *Query {
+	q.deletedScope = scopeWithDeleted
+	return q
+}
+
+// OnlyDeleted limits query results to soft deleted records.
+//
+//	q.OnlyDeleted().All(&[]User{})
+func (q *Query) OnlyDeleted() *Query {
+	q.deletedScope = scopeOnlyDeleted
+	return q
+}
+
+// Restore brings back soft deleted model by clearing its `deleted_at` column.
+func (c *Connection) Restore(model interface{}) error {
+	m := &Model{Value: model}
+	if !m.softDeletable() {
+		return errors.Errorf("%s does not support soft deletes", m.TableName())
+	}
+
+	stmt, err := c.Dialect.UpdateStmt(m.TableName(), "deleted_at = NULL", m.WhereID())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = c.Store.ExecContext(c.Context(), stmt)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	fbn, _ := m.fieldByName("DeletedAt")
+	fbn.Set(reflect.Zero(fbn.Type()))
+	return nil
+}
+
+// softDelete marks model as deleted by setting its `deleted_at` column
+func (c *Connection) softDelete(m *Model) error {
+	if err := m.beforeDelete(c); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	stmt, err := c.Dialect.UpdateStmt(m.TableName(), "deleted_at = ?", m.WhereID())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	stmt = c.Dialect.TranslateSQL(stmt)
+
+	_, err = c.Store.ExecContext(c.Context(), stmt, now)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	m.touchDeletedAt(now)
+
+	return m.afterDelete(c)
+}
+
+// softDeletable checks if model has nullable DeletedAt field
+// (`*time.Time` or `nulls.Time`) used for soft deletes
+func (m *Model) softDeletable() bool {
+	t := reflect.TypeOf(m.Value)
+	if t == nil {
+		return false
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	f, ok := t.FieldByName("DeletedAt")
+	if !ok {
+		return false
+	}
+	return f.Type == reflect.TypeOf(&time.Time{}) || f.Type == reflect.TypeOf(nulls.Time{})
+}
+
+// touchDeletedAt sets given time to DeletedAt field
+func (m *Model) touchDeletedAt(now time.Time) {
+	fbn, err := m.fieldByName("DeletedAt")
+	if err != nil {
+		return
+	}
+	switch fbn.Interface().(type) {
+	case *time.Time:
+		fbn.Set(reflect.ValueOf(&now))
+	case nulls.Time:
+		fbn.Set(reflect.ValueOf(nulls.NewTime(now)))
+	}
+}
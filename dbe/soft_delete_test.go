@@ -0,0 +1,69 @@
+package dbe
+
+import (
+	"testing"
+
+	"github.com/sedind/flow/dbe/nulls"
+)
+
+// testPost is soft deleted model
+type testPost struct {
+	ID        int        `db:"id"`
+	Title     string     `db:"title"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+}
+
+func (testPost) TableName() string { return "posts" }
+
+const createPosts = "CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT NOT NULL, deleted_at DATETIME NULL)"
+
+func TestConnection_SoftDelete(t *testing.T) {
+	c := newTestConnection(t, createPosts)
+
+	posts := []testPost{{Title: "first"}, {Title: "second"}}
+	if err := c.CreateMany(&posts); err != nil {
+		t.Fatal(err)
+	}
+
+	p := posts[0]
+	if err := c.Delete(&p); err != nil {
+		t.Fatal(err)
+	}
+	if !p.DeletedAt.Valid {
+		t.Fatal("expected deleted_at to be set")
+	}
+
+	counts := func() (int, int, int) {
+		t.Helper()
+		active, err := c.Query().Count(&testPost{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		all, err := c.Query().WithDeleted().Count(&testPost{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		deleted, err := c.Query().OnlyDeleted().Count(&testPost{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return active, all, deleted
+	}
+	if active, all, deleted := counts(); active != 1 || all != 2 || deleted != 1 {
+		t.Fatalf("expected 1 active, 2 total and 1 deleted post, got %d, %d and %d", active, all, deleted)
+	}
+
+	if err := c.Restore(&p); err != nil {
+		t.Fatal(err)
+	}
+	if active, _, _ := counts(); active != 2 {
+		t.Fatalf("expected 2 active posts after restore, got %d", active)
+	}
+
+	if err := c.HardDelete(&p); err != nil {
+		t.Fatal(err)
+	}
+	if _, all, _ := counts(); all != 1 {
+		t.Fatalf("expected 1 post after hard delete, got %d", all)
+	}
+}
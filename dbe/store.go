@@ -1,6 +1,7 @@
 package dbe
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
@@ -14,7 +15,14 @@ type Store interface {
 	NamedExec(string, interface{}) (sql.Result, error)
 	Exec(string, ...interface{}) (sql.Result, error)
 	PrepareNamed(string) (*sqlx.NamedStmt, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+	SelectContext(context.Context, interface{}, string, ...interface{}) error
+	GetContext(context.Context, interface{}, string, ...interface{}) error
+	NamedExecContext(context.Context, string, interface{}) (sql.Result, error)
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareNamedContext(context.Context, string) (*sqlx.NamedStmt, error)
 	Transaction() (*Tx, error)
+	TransactionContext(context.Context) (*Tx, error)
 	Rollback() error
 	Commit() error
 	Close() error
@@ -0,0 +1,360 @@
+package dbe
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SecretColumns lists parts of column names whose values are masked in SQL logs
+var SecretColumns = []string{"password", "secret", "token", "api_key", "apikey"}
+
+// maskedValue replaces values of secret columns in SQL logs
+const maskedValue = "*****"
+
+// rNamedPlaceholder matches named parameters of statements bound from models
+var rNamedPlaceholder = regexp.MustCompile(`[^:]:(\w+)`)
+
+// QueryEvent describes statement executed on connection
+type QueryEvent struct {
+	// Statement is the SQL statement as sent to the database
+	Statement string
+	// Args are values of statement parameters
+	Args []interface{}
+	// TxID is id of transaction statement is executed in, 0 outside of transaction
+	TxID int
+	// Duration of statement execution, set once statement is executed
+	Duration time.Duration
+	// Rows is number of returned or affected rows, -1 if unknown.
+	// Set once statement is executed.
+	Rows int64
+	// Err is error returned by statement execution, set once statement is executed
+	Err error
+
+	names []string
+	start time.Time
+}
+
+// MaskedArgs returns statement arguments with values bound to
+// `SecretColumns` replaced, so they are safe for logging.
+// Arguments are masked only when their columns are known, see `argNames`.
+func (e *QueryEvent) MaskedArgs() []interface{} {
+	masked := make([]interface{}, len(e.Args))
+	for i, arg := range e.Args {
+		masked[i] = arg
+		if i < len(e.names) && isSecretColumn(e.names[i]) {
+			masked[i] = maskedValue
+		}
+	}
+	return masked
+}
+
+// instrumentedStore wraps Store, runs connection hooks around every executed
+// statement and logs it at debug level with its arguments, number of affected
+// rows, elapsed time and transaction id. Statements running longer than
+// slowThreshold are logged as warnings. Executions of prepared statements
+// are instrumented by their callers using `Connection.instrument`.
+type instrumentedStore struct {
+	Store
+	txID          int
+	slowThreshold time.Duration
+	hooks         *hookSet
+}
+
+// newInstrumentedStore wraps given store of connection into instrumentedStore
+func newInstrumentedStore(s Store, txID int, c *Connection) *instrumentedStore {
+	return &instrumentedStore{
+		Store:         s,
+		txID:          txID,
+		slowThreshold: c.Details.SlowQueryThreshold(),
+		hooks:         c.hooks,
+	}
+}
+
+func (s *instrumentedStore) QueryRow(query string, args ...interface{}) *sql.Row {
+	return s.QueryRowContext(context.Background(), query, args...)
+}
+
+func (s *instrumentedStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (s *instrumentedStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.GetContext(context.Background(), dest, query, args...)
+}
+
+func (s *instrumentedStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return s.NamedExecContext(context.Background(), query, arg)
+}
+
+func (s *instrumentedStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s *instrumentedStore) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, e := s.before(ctx, query, args, nil)
+	row := s.Store.QueryRowContext(ctx, query, args...)
+	s.after(ctx, e, -1, row.Err())
+	return row
+}
+
+func (s *instrumentedStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, e := s.before(ctx, query, args, nil)
+	err := s.Store.SelectContext(ctx, dest, query, args...)
+	rows := int64(-1)
+	if v := reflect.Indirect(reflect.ValueOf(dest)); v.Kind() == reflect.Slice {
+		rows = int64(v.Len())
+	}
+	s.after(ctx, e, rows, err)
+	return err
+}
+
+func (s *instrumentedStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, e := s.before(ctx, query, args, nil)
+	err := s.Store.GetContext(ctx, dest, query, args...)
+	rows := int64(1)
+	if err != nil {
+		rows = 0
+	}
+	s.after(ctx, e, rows, err)
+	return err
+}
+
+func (s *instrumentedStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	ctx, e := s.beforeNamed(ctx, query, arg)
+	res, err := s.Store.NamedExecContext(ctx, query, arg)
+	s.after(ctx, e, rowsAffected(res), err)
+	return res, err
+}
+
+func (s *instrumentedStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, e := s.before(ctx, query, args, nil)
+	res, err := s.Store.ExecContext(ctx, query, args...)
+	s.after(ctx, e, rowsAffected(res), err)
+	return res, err
+}
+
+// PingContext verifies connection to the datasource is alive
+func (s *instrumentedStore) PingContext(ctx context.Context) error {
+	if p, ok := s.Store.(pinger); ok {
+		return p.PingContext(ctx)
+	}
+	_, err := s.Store.ExecContext(ctx, "SELECT 1")
+	return err
+}
+
+// instrument runs fn executing prepared named statement of connection store
+// as instrumented statement
+func (c *Connection) instrument(query string, arg interface{}, fn func(ctx context.Context) (sql.Result, error)) (sql.Result, error) {
+	s, ok := c.Store.(*instrumentedStore)
+	if !ok {
+		return fn(c.Context())
+	}
+
+	ctx, e := s.beforeNamed(c.Context(), query, arg)
+	res, err := fn(ctx)
+	s.after(ctx, e, rowsAffected(res), err)
+	return res, err
+}
+
+// beforeNamed starts instrumenting statement using named parameters bound from arg
+func (s *instrumentedStore) beforeNamed(ctx context.Context, query string, arg interface{}) (context.Context, *QueryEvent) {
+	names := []string{}
+	for _, m := range rNamedPlaceholder.FindAllStringSubmatch(query, -1) {
+		names = append(names, m[1])
+	}
+	_, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		args = nil
+	}
+	return s.before(ctx, query, args, names)
+}
+
+// before starts instrumenting statement and runs BeforeQuery hooks.
+// names are names of columns args are bound to, they are read
+// from query when statement uses positional parameters.
+func (s *instrumentedStore) before(ctx context.Context, query string, args []interface{}, names []string) (context.Context, *QueryEvent) {
+	if names == nil {
+		names = argNames(query)
+	}
+	e := &QueryEvent{
+		Statement: query,
+		Args:      args,
+		TxID:      s.txID,
+		names:     names,
+	}
+	for _, h := range s.hooks.list() {
+		ctx = h.BeforeQuery(ctx, e)
+	}
+	e.start = time.Now()
+	return ctx, e
+}
+
+// after finishes instrumenting statement, logs it and runs AfterQuery hooks.
+// Negative rows are not reported.
+func (s *instrumentedStore) after(ctx context.Context, e *QueryEvent, rows int64, err error) {
+	e.Duration = time.Since(e.start)
+	e.Rows = rows
+	if err != sql.ErrNoRows {
+		e.Err = err
+	}
+
+	s.log(e)
+
+	for _, h := range s.hooks.list() {
+		h.AfterQuery(ctx, e)
+	}
+}
+
+// log logs statement and its arguments as separate fields, masking values
+// of arguments bound to secret columns
+func (s *instrumentedStore) log(e *QueryEvent) {
+	fields := map[string]interface{}{
+		"sql":     e.Statement,
+		"args":    e.MaskedArgs(),
+		"elapsed": e.Duration,
+	}
+	if e.Rows >= 0 {
+		fields["rows"] = e.Rows
+	}
+	if e.TxID != 0 {
+		fields["tx_id"] = e.TxID
+	}
+	if e.Err != nil {
+		fields["error"] = e.Err.Error()
+	}
+
+	l := Logger.WithFields(fields)
+	if s.slowThreshold > 0 && e.Duration >= s.slowThreshold {
+		l.Warn("slow query")
+		return
+	}
+	l.Debug("query")
+}
+
+// argNames returns names of columns positional parameters of query are bound
+// to, e.g. `password` of `password = ?` or `password IN (?, ?)` and columns
+// listed by INSERT statement for its VALUES. Name is empty when column
+// of parameter is not known.
+func argNames(query string) []string {
+	names := []string{}
+	// ident is the last identifier, compared is column compared by operator
+	var ident, compared string
+	// group holds identifiers of the last parenthesized list, which are
+	// columns of INSERT statement when it is followed by VALUES
+	var group, insertColumns []string
+	values := false
+
+	for i := 0; i < len(query); {
+		ch := query[i]
+		switch {
+		case ch == '?' || ch == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			name := compared
+			if values && len(insertColumns) > 0 {
+				name = insertColumns[len(names)%len(insertColumns)]
+			}
+			names = append(names, name)
+			for i++; i < len(query) && isDigit(query[i]); i++ {
+			}
+			continue
+		case ch == '\'':
+			// skip string literal
+			end := strings.IndexByte(query[i+1:], '\'')
+			if end < 0 {
+				return names
+			}
+			i += end + 2
+			ident, compared = "", ""
+			continue
+		case ch == '"' || ch == '`' || isIdentChar(ch):
+			word, next := scanIdent(query, i)
+			i = next
+			switch strings.ToLower(word) {
+			case "like", "ilike", "in", "not", "is":
+				compared = ident
+			case "values":
+				insertColumns, values = group, true
+				if len(names) > 0 {
+					// placeholders precede VALUES, so this is not INSERT columns list
+					insertColumns = nil
+				}
+			case "and", "or", "where", "set", "on", "select", "from", "returning", "limit", "offset":
+				ident, compared, values = "", "", false
+			default:
+				ident, compared = word, ""
+				group = append(group, word)
+			}
+			continue
+		case ch == '(':
+			group = nil
+		case ch == '=' || ch == '<' || ch == '>' || ch == '!':
+			compared = ident
+		case ch == ',' || ch == ')' || ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+		default:
+			ident, compared = "", ""
+		}
+		i++
+	}
+	return names
+}
+
+// scanIdent reads identifier or quoted identifier starting at i,
+// qualified identifiers are read as a whole
+func scanIdent(query string, i int) (string, int) {
+	start := i
+	for i < len(query) {
+		ch := query[i]
+		switch {
+		case ch == '"' || ch == '`':
+			end := strings.IndexByte(query[i+1:], ch)
+			if end < 0 {
+				return query[start:], len(query)
+			}
+			i += end + 2
+		case isIdentChar(ch) || ch == '.':
+			i++
+		default:
+			return query[start:i], i
+		}
+	}
+	return query[start:], i
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || isDigit(ch) || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// isSecretColumn reports whether values of given column should be masked
+func isSecretColumn(name string) bool {
+	name = strings.ToLower(columnName(name))
+	if name == "" {
+		return false
+	}
+	for _, secret := range SecretColumns {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// rowsAffected returns number of rows affected by statement, -1 if unknown
+func rowsAffected(res sql.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
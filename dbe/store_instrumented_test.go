@@ -0,0 +1,137 @@
+package dbe
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sedind/flow/logger"
+)
+
+// logEntry is statement logged by recordingLogger
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// recordingLogger records entries logged at debug and warn levels
+type recordingLogger struct {
+	logger.Logger
+	fields  map[string]interface{}
+	entries *[]logEntry
+}
+
+func (l recordingLogger) WithFields(fields map[string]interface{}) logger.Logger {
+	return recordingLogger{l.Logger, fields, l.entries}
+}
+
+func (l recordingLogger) Debug(args ...interface{}) {
+	*l.entries = append(*l.entries, logEntry{"debug", fmt.Sprint(args...), l.fields})
+}
+
+func (l recordingLogger) Warn(args ...interface{}) {
+	*l.entries = append(*l.entries, logEntry{"warn", fmt.Sprint(args...), l.fields})
+}
+
+// recordLogs replaces Logger with recordingLogger until the end of the test
+func recordLogs(t *testing.T) *[]logEntry {
+	entries := &[]logEntry{}
+	prev := Logger
+	Logger = recordingLogger{logger.New("panic"), nil, entries}
+	t.Cleanup(func() { Logger = prev })
+	return entries
+}
+
+// testAccountUser is model of users table with secret column
+type testAccountUser struct {
+	ID       int    `db:"id"`
+	Name     string `db:"name"`
+	Password string `db:"password"`
+}
+
+func (testAccountUser) TableName() string { return "users" }
+
+func TestArgNames(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"UPDATE users SET name = ?, password = ? WHERE users.id = $3", []string{"name", "password", "users.id"}},
+		{"INSERT INTO users (users.name, api_token) VALUES (?, ?), (?, ?)", []string{"users.name", "api_token", "users.name", "api_token"}},
+		{"SELECT * FROM users WHERE password IN (?, ?) AND name NOT LIKE ? LIMIT ?", []string{"password", "password", "name", ""}},
+		{`SELECT * FROM users WHERE note = 'password = ?' AND "token" = ?`, []string{`"token"`}},
+	}
+	for _, tt := range tests {
+		names := argNames(tt.query)
+		if fmt.Sprint(names) != fmt.Sprint(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.expected, names)
+		}
+	}
+}
+
+func TestInstrumentedStore_MaskedArgs(t *testing.T) {
+	c := newTestConnection(t, "ALTER TABLE users ADD COLUMN password TEXT")
+	entries := recordLogs(t)
+
+	users := []testAccountUser{{Name: "a", Password: "secret-a"}, {Name: "b", Password: "secret-b"}}
+	if err := c.CreateMany(&users); err != nil {
+		t.Fatal(err)
+	}
+	users[0].Password = "secret-c"
+	if err := c.Update(&users[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Query().Where("password = ?", "secret-b").All(&[]testAccountUser{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*entries) != 3 {
+		t.Fatalf("expected 3 logged statements, got %d", len(*entries))
+	}
+	for _, e := range *entries {
+		if e.level != "debug" || e.msg != "query" {
+			t.Errorf("expected debug query entry, got %s %s", e.level, e.msg)
+		}
+		args := fmt.Sprint(e.fields["args"])
+		if strings.Contains(args, "secret") || !strings.Contains(args, maskedValue) {
+			t.Errorf("expected masked password of %s, got %s", e.fields["sql"], args)
+		}
+	}
+	if args := fmt.Sprint((*entries)[0].fields["args"]); !strings.Contains(args, "a") || !strings.Contains(args, "b") {
+		t.Errorf("expected names not to be masked, got %s", args)
+	}
+}
+
+func TestInstrumentedStore_SlowQuery(t *testing.T) {
+	c := newTestConnection(t)
+	entries := recordLogs(t)
+
+	c.Details.Options = map[string]string{"slow_query_threshold": "1ns"}
+	err := c.Transaction(func(tx *Connection) error {
+		return tx.Create(&testUser{Name: "a"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*entries) == 0 {
+		t.Fatal("expected logged statements")
+	}
+	found := false
+	for _, e := range *entries {
+		if e.fields["tx_id"] == nil {
+			continue
+		}
+		found = true
+		if e.level != "warn" || e.msg != "slow query" {
+			t.Errorf("expected slow query warning, got %s %s", e.level, e.msg)
+		}
+		if e.fields["elapsed"] == nil || e.fields["rows"] != int64(1) {
+			t.Errorf("expected elapsed time and affected rows, got %v", e.fields)
+		}
+	}
+	if !found {
+		t.Fatal("expected statements logged with transaction id")
+	}
+}
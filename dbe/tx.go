@@ -1,6 +1,7 @@
 package dbe
 
 import (
+	"context"
 	"math/rand"
 	"time"
 
@@ -18,11 +19,11 @@ type Tx struct {
 	*sqlx.Tx
 }
 
-func newTx(db *db) (*Tx, error) {
+func newTx(ctx context.Context, db *db) (*Tx, error) {
 	t := &Tx{
 		ID: rand.Int(),
 	}
-	tx, err := db.Beginx()
+	tx, err := db.BeginTxx(ctx, nil)
 	t.Tx = tx
 	return t, errors.Wrap(err, "could not create new transaction")
 }
@@ -33,6 +34,12 @@ func (tx *Tx) Transaction() (*Tx, error) {
 	return tx, nil
 }
 
+// TransactionContext simply returns the current transaction,
+// this is defined so it implements the `Store` interface.
+func (tx *Tx) TransactionContext(ctx context.Context) (*Tx, error) {
+	return tx, nil
+}
+
 // Close does nothing, it just respects Store interface
 func (tx *Tx) Close() error {
 	return nil
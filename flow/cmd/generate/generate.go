@@ -4,12 +4,19 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var configFile, migrationsPath string
+var configFile, migrationsPath, modelsPath, modelPath, handlersPath string
 
 // Bind package commands to parent command
 func Bind(parentCmd *cobra.Command) {
 	parentCmd.AddCommand(migrationCmd)
+	parentCmd.AddCommand(modelCmd)
+	parentCmd.AddCommand(resourceCmd)
 
 	parentCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "config.yml", "Configuration file path")
 	parentCmd.PersistentFlags().StringVarP(&migrationsPath, "target", "t", "", "Target path where migration will be generated")
+
+	migrationCmd.Flags().StringVar(&modelsPath, "from-models", "", "Generate migration from model structs in path compared to the database schema")
+	modelCmd.Flags().StringVar(&modelPath, "path", "models", "Path of models package where model will be generated")
+	resourceCmd.Flags().StringVar(&modelPath, "path", "models", "Path of models package where model will be generated")
+	resourceCmd.Flags().StringVar(&handlersPath, "handlers", "handlers", "Path of handlers package where resource handlers will be generated")
 }
@@ -21,30 +21,45 @@ var migrationCmd = &cobra.Command{
 		if len(args) == 0 {
 			return errors.New("You must supply a name for your migration")
 		}
-		if migrationsPath != "" {
-			// we will ignore project configuration and use migrations path to generate migration
-			return generateMigrationFile(migrationsPath, args[0], "sql", nil, nil)
+		if modelsPath != "" {
+			return generateModelsMigration(args[0], modelsPath)
 		}
 
-		if configFile == "" {
-			return errors.New("config file not provided")
+		path, err := migrationsTarget()
+		if err != nil {
+			return err
 		}
 
-		var path struct {
-			Path string `yaml:"migrations_path"`
-		}
+		return generateMigrationFile(path, args[0], "sql", nil, nil)
+	},
+}
 
-		err := config.LoadFromPath(configFile, &path)
-		if err != nil {
-			return errors.Wrapf(err, "Unable to load configuration %s", configFile)
-		}
+// migrationsTarget returns path where migrations are generated,
+// it is read from project configuration unless target path is provided
+func migrationsTarget() (string, error) {
+	if migrationsPath != "" {
+		// we will ignore project configuration and use migrations path to generate migration
+		return migrationsPath, nil
+	}
 
-		if path.Path == "" {
-			return errors.New("migrations_path can not be empty in configuration file")
-		}
+	if configFile == "" {
+		return "", errors.New("config file not provided")
+	}
 
-		return generateMigrationFile(path.Path, args[0], "sql", nil, nil)
-	},
+	var path struct {
+		Path string `yaml:"migrations_path"`
+	}
+
+	err := config.LoadFromPath(configFile, &path)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to load configuration %s", configFile)
+	}
+
+	if path.Path == "" {
+		return "", errors.New("migrations_path can not be empty in configuration file")
+	}
+
+	return path.Path, nil
 }
 
 // generateMigrationFile writes contents for a given migration in normalized files
@@ -0,0 +1,320 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/flow"
+	"github.com/sedind/flow/config"
+	"github.com/sedind/flow/dbe"
+	"github.com/sedind/flow/dbe/dialect"
+	"github.com/sedind/flow/dotenv"
+	"github.com/sedind/inflect"
+)
+
+// modelField is a struct field mapped to table column with `db` tag
+type modelField struct {
+	Column string
+	GoType string
+}
+
+// modelStruct is a struct with fields mapped to table columns
+type modelStruct struct {
+	Name   string
+	Table  string
+	Fields []modelField
+}
+
+// nullTypes maps nullable wrapper types to types they wrap
+var nullTypes = map[string]string{
+	"nulls.Bool":      "bool",
+	"nulls.ByteSlice": "[]byte",
+	"nulls.Float32":   "float32",
+	"nulls.Float64":   "float64",
+	"nulls.Int":       "int",
+	"nulls.Int32":     "int32",
+	"nulls.Int64":     "int64",
+	"nulls.String":    "string",
+	"nulls.Time":      "time.Time",
+	"nulls.UInt32":    "uint32",
+	"sql.NullBool":    "bool",
+	"sql.NullFloat64": "float64",
+	"sql.NullInt32":   "int32",
+	"sql.NullInt64":   "int64",
+	"sql.NullString":  "string",
+	"sql.NullTime":    "time.Time",
+}
+
+// generateModelsMigration generates migration which brings the schema of
+// default connection database in sync with models defined in modelsPath
+func generateModelsMigration(name string, modelsPath string) error {
+	dotenv.Load()
+
+	if configFile == "" {
+		return errors.New("config file not provided")
+	}
+
+	appConfig := flow.Config{}
+	err := config.LoadFromPath(configFile, &appConfig)
+	if err != nil {
+		return errors.Wrapf(err, "Unable to load configuration %s", configFile)
+	}
+
+	path := migrationsPath
+	if path == "" {
+		path = appConfig.MigrationsPath
+	}
+	if path == "" {
+		return errors.New("migrations_path can not be empty in configuration file")
+	}
+
+	cd, ok := appConfig.ConnectionStrings[appConfig.DefaultConnection]
+	if !ok {
+		return errors.Errorf("Default Connection String configuration not provided in %s", configFile)
+	}
+
+	conn, err := dbe.NewConnection(*cd)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create database connection")
+	}
+
+	err = conn.Open()
+	if err != nil {
+		return errors.Wrapf(err, "Unable to connect to `%s` connection", appConfig.DefaultConnection)
+	}
+	defer conn.Close()
+
+	models, err := parseModels(modelsPath)
+	if err != nil {
+		return err
+	}
+
+	up, down, err := modelsMigration(conn, models)
+	if err != nil {
+		return err
+	}
+	if len(up) == 0 {
+		return errors.Errorf("Database schema is up to date with models in %s", modelsPath)
+	}
+
+	return generateMigrationFile(path, name, "sql", up, down)
+}
+
+// parseModels parses Go source files in dir and returns structs
+// having fields mapped to table columns using `db` tag
+func parseModels(dir string) ([]modelStruct, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse models in %s", dir)
+	}
+
+	tableNames := map[string]string{}
+	models := []modelStruct{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					if typeName, table, ok := tableNameMethod(d); ok {
+						tableNames[typeName] = table
+					}
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						ts, ok := spec.(*ast.TypeSpec)
+						if !ok {
+							continue
+						}
+						st, ok := ts.Type.(*ast.StructType)
+						if !ok {
+							continue
+						}
+						if m := parseModel(fset, ts.Name.Name, st); len(m.Fields) > 0 {
+							models = append(models, m)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for i, m := range models {
+		if table, ok := tableNames[m.Name]; ok {
+			models[i].Table = table
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Table < models[j].Table })
+	return models, nil
+}
+
+// parseModel reads fields of struct mapped to table columns
+func parseModel(fset *token.FileSet, name string, st *ast.StructType) modelStruct {
+	m := modelStruct{Name: name, Table: inflect.Tableize(name)}
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		column := reflect.StructTag(tag).Get("db")
+		if column == "" || column == "-" {
+			continue
+		}
+
+		var buff bytes.Buffer
+		printer.Fprint(&buff, fset, field.Type)
+		m.Fields = append(m.Fields, modelField{Column: column, GoType: buff.String()})
+	}
+	return m
+}
+
+// tableNameMethod reads table name returned by TableName method of model
+func tableNameMethod(d *ast.FuncDecl) (string, string, bool) {
+	if d.Name.Name != "TableName" || d.Recv == nil || len(d.Recv.List) == 0 || d.Body == nil || len(d.Body.List) != 1 {
+		return "", "", false
+	}
+
+	recv := d.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	ident, ok := recv.(*ast.Ident)
+	if !ok {
+		return "", "", false
+	}
+
+	ret, ok := d.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", false
+	}
+	table, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", false
+	}
+	return ident.Name, table, true
+}
+
+// modelsMigration creates up and down SQL which brings the database schema
+// in sync with models. Missing tables are created and missing columns are added,
+// columns which are not mapped by models are reported but never dropped.
+func modelsMigration(conn *dbe.Connection, models []modelStruct) ([]byte, []byte, error) {
+	d := conn.Dialect
+	var up, down []string
+	for _, m := range models {
+		schema, err := conn.TableSchema(m.Table)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(schema) == 0 {
+			columns := []dialect.Column{}
+			for _, f := range m.Fields {
+				col, err := modelColumn(f)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "%s model", m.Name)
+				}
+				columns = append(columns, col)
+			}
+
+			create, err := d.CreateTableStmt(m.Table, columns)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			drop, err := d.DropTableStmt(m.Table)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			up = append(up, create+";")
+			down = append([]string{drop + ";"}, down...)
+			continue
+		}
+
+		existing := map[string]bool{}
+		for _, col := range schema {
+			existing[col.Name] = true
+		}
+
+		mapped := map[string]bool{}
+		for _, f := range m.Fields {
+			mapped[f.Column] = true
+			if existing[f.Column] {
+				continue
+			}
+
+			col, err := modelColumn(f)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "%s model", m.Name)
+			}
+			add, err := d.AddColumnStmt(m.Table, col)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			drop, err := d.DropColumnStmt(m.Table, col.Name)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			if !col.Null {
+				up = append(up, fmt.Sprintf("-- NOT NULL column requires DEFAULT value when %s table has rows", m.Table))
+			}
+			up = append(up, add+";")
+			down = append([]string{drop + ";"}, down...)
+		}
+
+		for _, col := range schema {
+			if mapped[col.Name] {
+				continue
+			}
+			drop, err := d.DropColumnStmt(m.Table, col.Name)
+			if err != nil {
+				return nil, nil, errors.WithStack(err)
+			}
+			up = append(up, fmt.Sprintf("-- %s; -- column is not mapped by %s model", drop, m.Name))
+		}
+	}
+
+	if len(down) == 0 {
+		// nothing to change, unmapped columns are only reported
+		return nil, nil, nil
+	}
+	return []byte(strings.Join(up, "\n") + "\n"), []byte(strings.Join(down, "\n") + "\n"), nil
+}
+
+// modelColumn creates dialect-neutral column of model field using column
+// types of generator field types. Pointers and nullable types are NULL columns,
+// integer `id` column is auto incremented primary key.
+func modelColumn(f modelField) (dialect.Column, error) {
+	goType := f.GoType
+	null := false
+	if strings.HasPrefix(goType, "*") {
+		null = true
+		goType = goType[1:]
+	}
+	if t, ok := nullTypes[goType]; ok {
+		null = true
+		goType = t
+	}
+
+	ft, ok := fieldTypes[goType]
+	if !ok {
+		return dialect.Column{}, errors.Errorf("unsupported type %s of %s column", f.GoType, f.Column)
+	}
+	return dialect.Column{Name: f.Column, Type: ft.FizzType, Null: null, Primary: f.Column == "id"}, nil
+}
@@ -0,0 +1,101 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sedind/flow/dbe"
+)
+
+const testModels = `package models
+
+import (
+	"time"
+
+	"github.com/sedind/flow/dbe/nulls"
+)
+
+type User struct {
+	ID        int          ` + "`db:\"id\"`" + `
+	Email     string       ` + "`db:\"email\"`" + `
+	Age       nulls.Int    ` + "`db:\"age\"`" + `
+	Bio       *string      ` + "`db:\"bio\"`" + `
+	CreatedAt time.Time    ` + "`db:\"created_at\"`" + `
+	Ignored   string       ` + "`db:\"-\"`" + `
+	Posts     []Post
+}
+
+type Post struct {
+	ID    int64  ` + "`db:\"id\"`" + `
+	Title string ` + "`db:\"title\"`" + `
+}
+
+func (Post) TableName() string { return "articles" }
+`
+
+func TestModelsMigration(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(testModels), 0644); err != nil {
+		t.Fatal(err)
+	}
+	models, err := parseModels(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := dbe.NewConnection(dbe.Details{Dialect: "sqlite", Database: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Store.Exec("CREATE TABLE articles (id INTEGER PRIMARY KEY AUTOINCREMENT, legacy TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	up, down, err := modelsMigration(conn, models)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUp := `-- NOT NULL column requires DEFAULT value when articles table has rows
+ALTER TABLE articles ADD COLUMN title VARCHAR(255) NOT NULL;
+-- ALTER TABLE articles DROP COLUMN legacy; -- column is not mapped by Post model
+CREATE TABLE users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	email VARCHAR(255) NOT NULL,
+	age INTEGER NULL,
+	bio VARCHAR(255) NULL,
+	created_at DATETIME NOT NULL
+);
+`
+	if string(up) != expectedUp {
+		t.Fatalf("expected up migration:\n%s\ngot:\n%s", expectedUp, up)
+	}
+	expectedDown := "DROP TABLE users;\nALTER TABLE articles DROP COLUMN title;\n"
+	if string(down) != expectedDown {
+		t.Fatalf("expected down migration:\n%s\ngot:\n%s", expectedDown, down)
+	}
+
+	if _, err := conn.Store.Exec(string(up)); err != nil {
+		t.Fatal(err)
+	}
+	up, down, err = modelsMigration(conn, models)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if up != nil || down != nil {
+		t.Fatalf("expected schema to be up to date, got:\n%s", up)
+	}
+}
+
+func TestModelsMigrationUnsupportedType(t *testing.T) {
+	_, err := modelColumn(modelField{Column: "tags", GoType: "[]string"})
+	if err == nil {
+		t.Fatal("expected error of unsupported type")
+	}
+}
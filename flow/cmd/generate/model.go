@@ -0,0 +1,237 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/inflect"
+	"github.com/spf13/cobra"
+)
+
+// modelCmd generates model and its create table migration
+var modelCmd = &cobra.Command{
+	Use:   "model [name] [field:type...]",
+	Short: "Generates model struct and migration which creates its table.",
+	Long: `Generates model struct and migration which creates its table.
+
+Field types are Go types (string, int, int64, float64, bool, time.Time, []byte),
+nullable types (nulls.String, nulls.Int, ...) or aliases text, timestamp, date and blob.
+
+	flow generate model user name:string email:string age:nulls.Int`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You must supply a name for your model")
+		}
+
+		m, err := newModelSpec(args[0], args[1:])
+		if err != nil {
+			return err
+		}
+
+		return generateModel(m)
+	},
+}
+
+// fieldType describes Go type of model field and fizz type of its column
+type fieldType struct {
+	GoType   string
+	FizzType string
+}
+
+// fieldTypes maps field types accepted by generators to model and column types
+var fieldTypes = map[string]fieldType{
+	"string":    {"string", "string"},
+	"text":      {"string", "text"},
+	"int":       {"int", "int"},
+	"int32":     {"int32", "int"},
+	"int64":     {"int64", "bigint"},
+	"uint32":    {"uint32", "bigint"},
+	"float32":   {"float32", "float"},
+	"float64":   {"float64", "float"},
+	"bool":      {"bool", "bool"},
+	"time.Time": {"time.Time", "timestamp"},
+	"timestamp": {"time.Time", "timestamp"},
+	"date":      {"time.Time", "date"},
+	"[]byte":    {"[]byte", "blob"},
+	"blob":      {"[]byte", "blob"},
+}
+
+// specField is a field of generated model
+type specField struct {
+	Name     string
+	Column   string
+	GoType   string
+	FizzType string
+	Null     bool
+}
+
+// modelSpec describes generated model
+type modelSpec struct {
+	Package string
+	Name    string
+	Table   string
+	File    string
+	Fields  []specField
+}
+
+// newModelSpec creates model spec from model name and field:type definitions
+func newModelSpec(name string, defs []string) (modelSpec, error) {
+	name = inflect.Singularize(inflect.Underscore(name))
+	m := modelSpec{
+		Package: filepath.Base(modelPath),
+		Name:    inflect.Camelize(name),
+		File:    filepath.Join(modelPath, name+".go"),
+	}
+	// table name is derived from struct name the same way `Model.TableName` does
+	m.Table = inflect.Tableize(m.Name)
+
+	for _, def := range defs {
+		parts := strings.SplitN(def, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return m, errors.Errorf("invalid field %s, expected name:type", def)
+		}
+
+		f := specField{
+			Name:   inflect.Camelize(parts[0]),
+			Column: inflect.Underscore(parts[0]),
+			GoType: parts[1],
+		}
+		switch f.Column {
+		case "id", "created_at", "updated_at":
+			return m, errors.Errorf("field %s is generated for every model", f.Column)
+		}
+
+		t := parts[1]
+		if base, ok := nullTypes[t]; ok {
+			f.Null = true
+			t = base
+		}
+		ft, ok := fieldTypes[t]
+		if !ok {
+			return m, errors.Errorf("unsupported type %s of %s field", parts[1], parts[0])
+		}
+		if !f.Null {
+			f.GoType = ft.GoType
+		}
+		f.FizzType = ft.FizzType
+
+		m.Fields = append(m.Fields, f)
+	}
+	return m, nil
+}
+
+// Receiver returns name of model method receiver
+func (m modelSpec) Receiver() string {
+	return strings.ToLower(m.Name[:1])
+}
+
+// Imports returns packages imported by model file
+func (m modelSpec) Imports() []string {
+	std := []string{}
+	var hasSQL, hasNulls bool
+	for _, f := range m.Fields {
+		hasSQL = hasSQL || strings.HasPrefix(f.GoType, "sql.")
+		hasNulls = hasNulls || strings.HasPrefix(f.GoType, "nulls.")
+	}
+	if hasSQL {
+		std = append(std, "database/sql")
+	}
+	std = append(std, "time", "")
+
+	pkgs := []string{"github.com/sedind/flow/dbe"}
+	if hasNulls {
+		pkgs = append(pkgs, "github.com/sedind/flow/dbe/nulls")
+	}
+	pkgs = append(pkgs, "github.com/sedind/flow/validate")
+	return append(std, pkgs...)
+}
+
+var modelTemplate = template.Must(template.New("model").Parse(`package {{ .Package }}
+
+import (
+{{- range .Imports }}
+	{{ if . }}"{{ . }}"{{ end }}
+{{- end }}
+)
+
+// {{ .Name }} model is stored in {{ .Table }} table
+type {{ .Name }} struct {
+	ID int ` + "`" + `db:"id" json:"id"` + "`" + `
+{{- range .Fields }}
+	{{ .Name }} {{ .GoType }} ` + "`" + `db:"{{ .Column }}" json:"{{ .Column }}"` + "`" + `
+{{- end }}
+	CreatedAt time.Time ` + "`" + `db:"created_at" json:"created_at"` + "`" + `
+	UpdatedAt time.Time ` + "`" + `db:"updated_at" json:"updated_at"` + "`" + `
+}
+
+// Validate validates {{ .Name }} before it is saved to database
+func ({{ .Receiver }} *{{ .Name }}) Validate(c *dbe.Connection) (*validate.Errors, error) {
+	return validate.Validate(), nil
+}
+`))
+
+var createTableTemplate = template.Must(template.New("create_table").Parse(`create_table("{{ .Table }}") {
+{{- range .Fields }}
+	t.Column("{{ .Column }}", "{{ .FizzType }}"{{ if .Null }}, {"null": true}{{ end }})
+{{- end }}
+	t.Timestamps()
+}
+`))
+
+// generateModel writes model file and migration which creates model table
+func generateModel(m modelSpec) error {
+	var buff bytes.Buffer
+	err := modelTemplate.Execute(&buff, m)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't render %s model", m.Name)
+	}
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "couldn't format %s model", m.Name)
+	}
+
+	err = writeNewFile(m.File, src)
+	if err != nil {
+		return err
+	}
+
+	path, err := migrationsTarget()
+	if err != nil {
+		return err
+	}
+
+	buff.Reset()
+	err = createTableTemplate.Execute(&buff, m)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't render %s table migration", m.Table)
+	}
+	down := []byte(fmt.Sprintf("drop_table(\"%s\")\n", m.Table))
+
+	return generateMigrationFile(path, "create_"+m.Table, "fizz", buff.Bytes(), down)
+}
+
+// writeNewFile writes generated file, existing files are never overwritten
+func writeNewFile(path string, content []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return errors.Errorf("%s already exists", path)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0766)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't create path %s", filepath.Dir(path))
+	}
+
+	err = ioutil.WriteFile(path, content, 0666)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't write %s", path)
+	}
+	fmt.Printf("> %s\n", path)
+	return nil
+}
@@ -0,0 +1,152 @@
+package generate
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"testing"
+	"text/template"
+)
+
+// testFset and testSources are shared by tests, so dependencies of
+// generated packages are type checked from source only once
+var (
+	testFset    = token.NewFileSet()
+	testSources = importer.ForCompiler(testFset, "source", nil).(types.ImporterFrom)
+)
+
+// testImporter resolves packages from source, generated packages
+// which are not on disk are resolved from pkgs
+type testImporter map[string]*types.Package
+
+func (i testImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i[path]; ok {
+		return pkg, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return testSources.ImportFrom(path, dir, 0)
+}
+
+// renderSource executes template with data and formats rendered Go source
+func renderSource(t *testing.T, tmpl *template.Template, data interface{}) []byte {
+	t.Helper()
+	var buff bytes.Buffer
+	if err := tmpl.Execute(&buff, data); err != nil {
+		t.Fatal(err)
+	}
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		t.Fatalf("couldn't format generated source: %v\n%s", err, buff.Bytes())
+	}
+	return src
+}
+
+// checkSource type checks generated source as package with given import path
+func checkSource(t *testing.T, path string, src []byte, pkgs map[string]*types.Package) *types.Package {
+	t.Helper()
+	f, err := parser.ParseFile(testFset, path+".go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: testImporter(pkgs)}
+	pkg, err := conf.Check(path, testFset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatalf("generated source doesn't compile: %v\n%s", err, src)
+	}
+	return pkg
+}
+
+// setModelPath sets models path flag for the duration of test
+func setModelPath(t *testing.T, path string) {
+	old := modelPath
+	modelPath = path
+	t.Cleanup(func() { modelPath = old })
+}
+
+func TestGenerateModel(t *testing.T) {
+	setModelPath(t, "models")
+
+	m, err := newModelSpec("blog_posts", []string{
+		"title:string",
+		"body:text",
+		"views:int64",
+		"rating:nulls.Float64",
+		"published_at:nulls.Time",
+		"score:sql.NullInt64",
+		"data:blob",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "BlogPost" || m.Table != "blog_posts" || m.Package != "models" {
+		t.Fatalf("unexpected model spec %+v", m)
+	}
+
+	pkg := checkSource(t, "example.com/app/models", renderSource(t, modelTemplate, m), nil)
+	obj := pkg.Scope().Lookup("BlogPost")
+	if obj == nil {
+		t.Fatal("expected BlogPost type in generated package")
+	}
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		t.Fatalf("expected BlogPost struct, got %s", obj.Type())
+	}
+	fields := map[string]string{}
+	for i := 0; i < st.NumFields(); i++ {
+		fields[st.Field(i).Name()] = types.TypeString(st.Field(i).Type(), (*types.Package).Name)
+	}
+	expected := map[string]string{
+		"ID":          "int",
+		"Title":       "string",
+		"Body":        "string",
+		"Views":       "int64",
+		"Rating":      "nulls.Float64",
+		"PublishedAt": "nulls.Time",
+		"Score":       "sql.NullInt64",
+		"Data":        "[]byte",
+		"CreatedAt":   "time.Time",
+		"UpdatedAt":   "time.Time",
+	}
+	for name, typ := range expected {
+		if fields[name] != typ {
+			t.Errorf("expected %s field of type %s, got %q", name, typ, fields[name])
+		}
+	}
+
+	var buff bytes.Buffer
+	if err := createTableTemplate.Execute(&buff, m); err != nil {
+		t.Fatal(err)
+	}
+	expectedFizz := `create_table("blog_posts") {
+	t.Column("title", "string")
+	t.Column("body", "text")
+	t.Column("views", "bigint")
+	t.Column("rating", "float", {"null": true})
+	t.Column("published_at", "timestamp", {"null": true})
+	t.Column("score", "bigint", {"null": true})
+	t.Column("data", "blob")
+	t.Timestamps()
+}
+`
+	if buff.String() != expectedFizz {
+		t.Fatalf("expected migration:\n%s\ngot:\n%s", expectedFizz, buff.String())
+	}
+}
+
+func TestNewModelSpecInvalidField(t *testing.T) {
+	setModelPath(t, "models")
+
+	for _, def := range []string{"title", "id:int", "tags:[]string"} {
+		if _, err := newModelSpec("post", []string{def}); err == nil {
+			t.Errorf("expected error of %s field", def)
+		}
+	}
+}
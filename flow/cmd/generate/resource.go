@@ -0,0 +1,303 @@
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/sedind/inflect"
+	"github.com/spf13/cobra"
+)
+
+// resourceCmd generates model, migration and REST handlers of resource
+var resourceCmd = &cobra.Command{
+	Use:   "resource [name] [field:type...]",
+	Short: "Generates model, migration and REST handlers of a resource.",
+	Long: `Generates model, migration and REST handlers of a resource.
+
+Handlers list, show, create, update and delete resource records and are
+registered on router by generated Route function.
+
+	flow generate resource user name:string email:string age:nulls.Int`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return errors.New("You must supply a name for your resource")
+		}
+
+		m, err := newModelSpec(args[0], args[1:])
+		if err != nil {
+			return err
+		}
+
+		res, err := newResourceSpec(m)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(res.File); err == nil {
+			return errors.Errorf("%s already exists", res.File)
+		}
+
+		err = generateModel(m)
+		if err != nil {
+			return err
+		}
+
+		return generateHandlers(res)
+	},
+}
+
+// resourceSpec describes generated resource handlers
+type resourceSpec struct {
+	modelSpec
+	Package      string
+	File         string
+	ModelsImport string
+	// ModelsPackage is name of models package
+	ModelsPackage string
+	// Plural is plural of model name, e.g. Users
+	Plural string
+}
+
+// newResourceSpec creates resource spec of model
+func newResourceSpec(m modelSpec) (resourceSpec, error) {
+	res := resourceSpec{
+		modelSpec:     m,
+		Package:       filepath.Base(handlersPath),
+		File:          filepath.Join(handlersPath, m.Table+".go"),
+		Plural:        inflect.Pluralize(m.Name),
+		ModelsPackage: m.Package,
+	}
+
+	module, err := modulePath()
+	if err != nil {
+		return res, err
+	}
+	if filepath.IsAbs(modelPath) {
+		return res, errors.Errorf("models path %s must be relative to project path", modelPath)
+	}
+	res.ModelsImport = path.Join(module, filepath.ToSlash(modelPath))
+	return res, nil
+}
+
+// Var returns variable name of single model
+func (r resourceSpec) Var() string {
+	return strings.ToLower(r.Name[:1]) + r.Name[1:]
+}
+
+// PluralVar returns variable name of model slice
+func (r resourceSpec) PluralVar() string {
+	return strings.ToLower(r.Plural[:1]) + r.Plural[1:]
+}
+
+// generateHandlers writes resource handlers file and prints
+// snippet which registers resource routes
+func generateHandlers(res resourceSpec) error {
+	var buff bytes.Buffer
+	err := handlersTemplate.Execute(&buff, res)
+	if err != nil {
+		return errors.Wrapf(err, "couldn't render %s handlers", res.Table)
+	}
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "couldn't format %s handlers", res.Table)
+	}
+
+	err = writeNewFile(res.File, src)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nRegister %s routes on your router:\n\n\tr.Route(\"/%s\", %s.Route%s(ctx))\n\n", res.Table, res.Table, res.Package, res.Plural)
+	return nil
+}
+
+// modulePath reads module path of project from go.mod
+func modulePath() (string, error) {
+	f, err := os.Open("go.mod")
+	if err != nil {
+		return "", errors.Wrap(err, "couldn't determine import path of models package")
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`), nil
+		}
+	}
+	return "", errors.New("couldn't determine import path of models package, module is not defined in go.mod")
+}
+
+var handlersTemplate = template.Must(template.New("handlers").Parse(`package {{ .Package }}
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/sedind/flow"
+	"github.com/sedind/flow/router"
+	"{{ .ModelsImport }}"
+)
+
+// Route{{ .Plural }} registers {{ .Table }} resource routes
+//
+//	r.Route("/{{ .Table }}", {{ .Package }}.Route{{ .Plural }}(ctx))
+func Route{{ .Plural }}(ctx *flow.Context) func(r router.Router) {
+	h := {{ .Plural }}Handler{ctx: ctx}
+	return func(r router.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.Show)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	}
+}
+
+// {{ .Plural }}Handler handles requests of {{ .Table }} resource
+type {{ .Plural }}Handler struct {
+	ctx *flow.Context
+}
+
+// List responds with a page of {{ .Table }}
+func (h {{ .Plural }}Handler) List(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.ctx.DefaultConnection()
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+
+	{{ .PluralVar }} := []{{ .ModelsPackage }}.{{ .Name }}{}
+	q := conn.Query().PaginateFromParams(r.URL.Query())
+	if err := q.All(&{{ .PluralVar }}); err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+
+	h.ctx.JSON(w, http.StatusOK, h.ctx.ResponsePage({{ .PluralVar }}, q.Paginator))
+}
+
+// Show responds with {{ .Name }} identified by id URL param
+func (h {{ .Plural }}Handler) Show(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.ctx.DefaultConnection()
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+
+	{{ .Var }} := {{ .ModelsPackage }}.{{ .Name }}{}
+	if err := conn.Query().Find(&{{ .Var }}, router.URLParam(r, "id")); err != nil {
+		status := http.StatusInternalServerError
+		if err == sql.ErrNoRows {
+			status = http.StatusNotFound
+		}
+		h.ctx.JSON(w, status, h.ctx.ResponseError(err))
+		return
+	}
+
+	h.ctx.JSON(w, http.StatusOK, h.ctx.ResponseData({{ .Var }}))
+}
+
+// Create validates and creates {{ .Name }} bound from request body
+func (h {{ .Plural }}Handler) Create(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.ctx.DefaultConnection()
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+
+	{{ .Var }} := {{ .ModelsPackage }}.{{ .Name }}{}
+	if err := h.ctx.Bind(r, &{{ .Var }}); err != nil {
+		h.ctx.JSON(w, http.StatusBadRequest, h.ctx.ResponseError(err))
+		return
+	}
+
+	verrs, err := conn.ValidateAndCreate(&{{ .Var }})
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+	if verrs.HasAny() {
+		h.ctx.JSON(w, http.StatusUnprocessableEntity, h.ctx.ResponseError(verrs))
+		return
+	}
+
+	h.ctx.JSON(w, http.StatusCreated, h.ctx.ResponseData({{ .Var }}))
+}
+
+// Update validates and updates {{ .Name }} identified by id URL param
+func (h {{ .Plural }}Handler) Update(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.ctx.DefaultConnection()
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+
+	{{ .Var }} := {{ .ModelsPackage }}.{{ .Name }}{}
+	if err := conn.Query().Find(&{{ .Var }}, router.URLParam(r, "id")); err != nil {
+		status := http.StatusInternalServerError
+		if err == sql.ErrNoRows {
+			status = http.StatusNotFound
+		}
+		h.ctx.JSON(w, status, h.ctx.ResponseError(err))
+		return
+	}
+
+	id := {{ .Var }}.ID
+	if err := h.ctx.Bind(r, &{{ .Var }}); err != nil {
+		h.ctx.JSON(w, http.StatusBadRequest, h.ctx.ResponseError(err))
+		return
+	}
+	{{ .Var }}.ID = id
+
+	verrs, err := conn.ValidateAndUpdate(&{{ .Var }})
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+	if verrs.HasAny() {
+		h.ctx.JSON(w, http.StatusUnprocessableEntity, h.ctx.ResponseError(verrs))
+		return
+	}
+
+	h.ctx.JSON(w, http.StatusOK, h.ctx.ResponseData({{ .Var }}))
+}
+
+// Delete deletes {{ .Name }} identified by id URL param
+func (h {{ .Plural }}Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.ctx.DefaultConnection()
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+
+	{{ .Var }} := {{ .ModelsPackage }}.{{ .Name }}{}
+	if err := conn.Query().Find(&{{ .Var }}, router.URLParam(r, "id")); err != nil {
+		status := http.StatusInternalServerError
+		if err == sql.ErrNoRows {
+			status = http.StatusNotFound
+		}
+		h.ctx.JSON(w, status, h.ctx.ResponseError(err))
+		return
+	}
+
+	verrs, err := conn.ValidateAndDelete(&{{ .Var }})
+	if err != nil {
+		h.ctx.JSON(w, http.StatusInternalServerError, h.ctx.ResponseError(err))
+		return
+	}
+	if verrs.HasAny() {
+		h.ctx.JSON(w, http.StatusUnprocessableEntity, h.ctx.ResponseError(verrs))
+		return
+	}
+
+	h.ctx.JSON(w, http.StatusOK, h.ctx.ResponseData({{ .Var }}))
+}
+`))
@@ -0,0 +1,90 @@
+package generate
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testResourceSpec creates resource spec of model in project with given module path
+func testResourceSpec(t *testing.T, module string, m modelSpec) resourceSpec {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+module+"\n\ngo 1.13\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	res, err := newResourceSpec(m)
+	if err := os.Chdir(wd); err != nil {
+		t.Fatal(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestGenerateHandlers(t *testing.T) {
+	setModelPath(t, "app/models")
+	old := handlersPath
+	handlersPath = "app/handlers"
+	defer func() { handlersPath = old }()
+
+	m, err := newModelSpec("blog_post", []string{"title:string", "rating:nulls.Float64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := testResourceSpec(t, "example.com/blog", m)
+	if res.ModelsImport != "example.com/blog/app/models" {
+		t.Fatalf("expected models import example.com/blog/app/models, got %s", res.ModelsImport)
+	}
+	if res.Package != "handlers" || res.File != filepath.Join("app/handlers", "blog_posts.go") {
+		t.Fatalf("unexpected resource spec %+v", res)
+	}
+	if res.Var() != "blogPost" || res.PluralVar() != "blogPosts" {
+		t.Fatalf("expected blogPost and blogPosts variables, got %s and %s", res.Var(), res.PluralVar())
+	}
+
+	models := checkSource(t, res.ModelsImport, renderSource(t, modelTemplate, m), nil)
+	pkg := checkSource(t, "example.com/blog/app/handlers", renderSource(t, handlersTemplate, res),
+		map[string]*types.Package{res.ModelsImport: models})
+
+	if pkg.Scope().Lookup("RouteBlogPosts") == nil {
+		t.Fatal("expected RouteBlogPosts function in generated package")
+	}
+	obj := pkg.Scope().Lookup("BlogPostsHandler")
+	if obj == nil {
+		t.Fatal("expected BlogPostsHandler type in generated package")
+	}
+	mset := types.NewMethodSet(obj.Type())
+	for _, name := range []string{"List", "Show", "Create", "Update", "Delete"} {
+		if mset.Lookup(pkg, name) == nil {
+			t.Errorf("expected %s handler method", name)
+		}
+	}
+}
+
+func TestNewResourceSpecWithoutModule(t *testing.T) {
+	setModelPath(t, "models")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	if _, err := newResourceSpec(modelSpec{Name: "User", Table: "users"}); err == nil {
+		t.Fatal("expected error of missing go.mod")
+	}
+}
@@ -50,7 +50,12 @@ var downCmd = &cobra.Command{
 		if err != nil {
 			return errors.Wrap(err, "Unable to create File Migration")
 		}
+		fm.Settings = appConfig.AppSettings
+		fm.DryRun = dryRun
 
+		if toVersion != "" {
+			return fm.DownTo(toVersion)
+		}
 		return fm.Down(1)
 	},
 }
@@ -6,12 +6,24 @@ import (
 
 var configFile string
 
+var dryRun bool
+
+var toVersion string
+
 // Bind package commands to parent command
 func Bind(parentCmd *cobra.Command) {
 	parentCmd.AddCommand(upCmd)
 	parentCmd.AddCommand(downCmd)
 	parentCmd.AddCommand(resetCmd)
 	parentCmd.AddCommand(statusCmd)
+	parentCmd.AddCommand(redoCmd)
 
 	parentCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "config.yml", "Configuration file path")
+
+	upCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print SQL of pending migrations without executing them")
+	downCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print SQL of migrations to roll back without executing them")
+	redoCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print SQL of migration to redo without executing it")
+
+	upCmd.Flags().StringVar(&toVersion, "to", "", "Apply migrations up to and including this version")
+	downCmd.Flags().StringVar(&toVersion, "to", "", "Roll back migrations newer than this version")
 }
@@ -50,6 +50,7 @@ var resetCmd = &cobra.Command{
 		if err != nil {
 			return errors.Wrap(err, "Unable to create File Migration")
 		}
+		fm.Settings = appConfig.AppSettings
 
 		return fm.Reset()
 	},
@@ -50,7 +50,12 @@ var upCmd = &cobra.Command{
 		if err != nil {
 			return errors.Wrap(err, "Unable to create File Migration")
 		}
+		fm.Settings = appConfig.AppSettings
+		fm.DryRun = dryRun
 
+		if toVersion != "" {
+			return fm.UpTo(toVersion)
+		}
 		return fm.Up()
 	},
 }
@@ -2,7 +2,8 @@ package flow
 
 // Response represents response object for API
 type Response struct {
-	Data    interface{} `json:"data"`
-	Error   interface{} `json:"error"`
-	Success bool        `json:"success"`
+	Data       interface{} `json:"data"`
+	Error      interface{} `json:"error"`
+	Success    bool        `json:"success"`
+	Pagination interface{} `json:"pagination,omitempty"`
 }